@@ -4,8 +4,10 @@ import (
 	"errors"
 	"log"
 	"os"
+	"runtime"
 	"strings"
 
+	"github.com/traefik/seo/robots"
 	"github.com/traefik/seo/sitemap"
 	"github.com/traefik/seo/transform"
 	"github.com/urfave/cli/v2"
@@ -16,25 +18,38 @@ func main() {
 		Name:        "seo",
 		Description: "Documentation modification for SEO.",
 		Usage:       "SEO doc",
-		Flags: []cli.Flag{
+		Flags: append(ruleFlags(),
+			&cli.BoolFlag{
+				Name:  transform.FlagDryRun,
+				Usage: "Reports the planned changes without writing them.",
+			},
+			&cli.BoolFlag{
+				Name:  transform.FlagDiff,
+				Usage: "Displays the unified diff of the <head> section of the modified pages.",
+			},
 			&cli.StringFlag{
-				Name:  transform.FlagPath,
-				Usage: "Path of the documentation.",
+				Name:  transform.FlagDiffFile,
+				Usage: "Writes the unified diff into a patch file (implies -diff).",
 			},
 			&cli.StringFlag{
-				Name:  transform.FlagProduct,
-				Usage: "Product name.",
+				Name:  transform.FlagReport,
+				Usage: "Writes the JSON report of the run into a file.",
 			},
-		},
+		),
 		Action: func(cliCtx *cli.Context) error {
-			config := transform.NewConfig(cliCtx)
+			config, err := transform.NewConfig(cliCtx)
+			if err != nil {
+				return err
+			}
 
-			err := validate(config)
+			err = validate(config)
 			if err != nil {
 				return err
 			}
 
-			return transform.Run(config)
+			_, err = transform.Run(config)
+
+			return err
 		},
 		Commands: []*cli.Command{
 			{
@@ -46,7 +61,27 @@ func main() {
 					return nil
 				},
 			},
+			{
+				Name:        "verify",
+				Usage:       "Verifies that the documentation is already in its final SEO state.",
+				Description: "Runs every transformation in memory and fails if a file would be modified.",
+				Flags:       ruleFlags(),
+				Action: func(cliCtx *cli.Context) error {
+					config, err := transform.NewConfig(cliCtx)
+					if err != nil {
+						return err
+					}
+
+					err = validate(config)
+					if err != nil {
+						return err
+					}
+
+					return transform.Verify(config)
+				},
+			},
 			sitemap.Command(),
+			robots.Command(),
 		},
 	}
 
@@ -56,6 +91,37 @@ func main() {
 	}
 }
 
+// ruleFlags returns the flags defining the documentation and the transformation rules.
+func ruleFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:  transform.FlagPath,
+			Usage: "Path of the documentation.",
+		},
+		&cli.StringFlag{
+			Name:  transform.FlagProduct,
+			Usage: "Product name.",
+		},
+		&cli.IntFlag{
+			Name:  transform.FlagConcurrency,
+			Usage: "Number of files processed in parallel.",
+			Value: runtime.GOMAXPROCS(0),
+		},
+		&cli.StringFlag{
+			Name:  transform.FlagConfig,
+			Usage: "Path of the configuration file of the transformation rules.",
+		},
+		&cli.BoolFlag{
+			Name:  transform.FlagReplaceCanonical,
+			Usage: "Replaces the canonical links pointing at another host or inside a versioned folder.",
+		},
+		&cli.StringFlag{
+			Name:  transform.FlagSince,
+			Usage: "Git revision, only the files added or modified since this revision are transformed.",
+		},
+	}
+}
+
 func validate(cfg transform.Config) error {
 	if strings.TrimSpace(cfg.Path) == "" {
 		return errors.New("path is required")
@@ -0,0 +1,49 @@
+package robots
+
+import (
+	"github.com/traefik/seo/versions"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	flagRoot           = "root"
+	flagKeepVersions   = "keep-versions"
+	flagDisallow       = "disallow"
+	flagVersionFolders = "version-folders"
+)
+
+// Command is the robots command.
+func Command() *cli.Command {
+	return &cli.Command{
+		Name:        "robots",
+		Usage:       "Generates robots.txt of the documentation.",
+		Description: "robots.txt generator.",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:   flagRoot,
+				Usage:  "Path to the root of the documentation.",
+				Value:  ".",
+				Hidden: true,
+			},
+			&cli.IntFlag{
+				Name:  flagKeepVersions,
+				Usage: "Number of versions of each product allowed to the crawlers, the older versions are disallowed (0 allows all the versions).",
+			},
+			&cli.StringSliceFlag{
+				Name:  flagDisallow,
+				Usage: "Disallowed product or version (e.g. traefik-pilot, traefik/v1.7).",
+			},
+			&cli.StringFlag{
+				Name:  flagVersionFolders,
+				Usage: "Pattern of the names of the version folders (default: " + versions.DefaultFolderPattern + ").",
+			},
+		},
+		Action: func(cliCtx *cli.Context) error {
+			return Generate(cliCtx.Path(flagRoot), Policy{
+				KeepVersions:   cliCtx.Int(flagKeepVersions),
+				Disallow:       cliCtx.StringSlice(flagDisallow),
+				VersionFolders: cliCtx.String(flagVersionFolders),
+			})
+		},
+	}
+}
@@ -0,0 +1,131 @@
+package robots
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/traefik/seo/sitemap"
+	"github.com/traefik/seo/versions"
+)
+
+const fileNameRobots = "robots.txt"
+
+// Policy defines the versions disallowed to the crawlers.
+type Policy struct {
+	// KeepVersions is the number of versions of a product allowed to the crawlers, the older versions are disallowed.
+	// Zero allows all the versions.
+	KeepVersions int
+	// Disallow are the disallowed products or versions (traefik, traefik/v1.7).
+	Disallow []string
+	// VersionFolders matches the names of the version folders, versions.DefaultFolderPattern is used if empty.
+	VersionFolders string
+}
+
+// Product is a product of the documentation and its version folders.
+type Product struct {
+	Name string
+	// Versions are the version folders, from the newest to the oldest.
+	Versions []string
+}
+
+// Generate generates the robots.txt file at the root of the documentation.
+func Generate(root string, policy Policy) error {
+	folders, err := versions.NewFolders(policy.VersionFolders)
+	if err != nil {
+		return err
+	}
+
+	products, err := Scan(root, folders)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(root, fileNameRobots), Build(products, policy), 0o644)
+}
+
+// Scan lists the products of the documentation and their version folders.
+func Scan(root string, folders *versions.Folders) ([]Product, error) {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil, err
+	}
+
+	var products []Product
+
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		set, errL := folders.List(filepath.Join(root, entry.Name()))
+		if errL != nil {
+			return nil, errL
+		}
+
+		product := Product{Name: entry.Name()}
+
+		for _, v := range set {
+			// the documentation of the development branch is not a released version.
+			if !v.Dev {
+				product.Versions = append(product.Versions, v.Name)
+			}
+		}
+
+		products = append(products, product)
+	}
+
+	return products, nil
+}
+
+// Build builds the content of the robots.txt file.
+func Build(products []Product, policy Policy) []byte {
+	disallowed := make(map[string]struct{})
+	for _, d := range policy.Disallow {
+		disallowed[strings.Trim(d, "/")] = struct{}{}
+	}
+
+	b := &bytes.Buffer{}
+
+	_, _ = fmt.Fprintln(b, "# Generated by the seo robots command, do not edit.")
+	_, _ = fmt.Fprintln(b, "User-agent: *")
+
+	var rules int
+
+	for _, product := range products {
+		var paths []string
+
+		if _, ok := disallowed[product.Name]; ok {
+			paths = append(paths, product.Name)
+		} else {
+			for i, version := range product.Versions {
+				_, listed := disallowed[product.Name+"/"+version]
+				if listed || (policy.KeepVersions > 0 && i >= policy.KeepVersions) {
+					paths = append(paths, product.Name+"/"+version)
+				}
+			}
+		}
+
+		if len(paths) == 0 {
+			continue
+		}
+
+		_, _ = fmt.Fprintf(b, "# %s\n", product.Name)
+
+		for _, p := range paths {
+			_, _ = fmt.Fprintf(b, "Disallow: /%s/\n", p)
+		}
+
+		rules += len(paths)
+	}
+
+	if rules == 0 {
+		_, _ = fmt.Fprintln(b, "Disallow:")
+	}
+
+	_, _ = fmt.Fprintf(b, "\nSitemap: %s\n", sitemap.URL)
+
+	return b.Bytes()
+}
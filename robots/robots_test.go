@@ -0,0 +1,132 @@
+package robots
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/seo/versions"
+)
+
+func TestScan(t *testing.T) {
+	folders, err := versions.NewFolders("")
+	require.NoError(t, err)
+
+	products, err := Scan("./fixtures/docs", folders)
+	require.NoError(t, err)
+
+	expected := []Product{
+		{Name: "traefik", Versions: []string{"v2.10", "v2.9", "v2.0", "v1.7"}},
+		{Name: "traefik-enterprise", Versions: []string{"v2.0", "v1.0"}},
+		{Name: "traefik-pilot"},
+	}
+
+	assert.Equal(t, expected, products)
+}
+
+func TestScan_versionFolders(t *testing.T) {
+	root := t.TempDir()
+
+	for _, dir := range []string{"traefik/version-2.1", "traefik/version-2.0", "traefik/v2.2", "traefik/2.3"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(root, dir), 0o700))
+	}
+
+	testCases := []struct {
+		desc     string
+		pattern  string
+		expected []string
+	}{
+		{
+			desc:     "default",
+			expected: []string{"v2.2"},
+		},
+		{
+			desc:     "prefixed folders",
+			pattern:  `^version-(?P<version>\d+\.\d+)$`,
+			expected: []string{"version-2.1", "version-2.0"},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			folders, err := versions.NewFolders(test.pattern)
+			require.NoError(t, err)
+
+			products, err := Scan(root, folders)
+			require.NoError(t, err)
+
+			assert.Equal(t, []Product{{Name: "traefik", Versions: test.expected}}, products)
+		})
+	}
+}
+
+func TestBuild(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		policy Policy
+		golden string
+	}{
+		{
+			desc:   "allow all",
+			golden: "robots-all.golden.txt",
+		},
+		{
+			desc: "policy",
+			policy: Policy{
+				KeepVersions: 2,
+				Disallow:     []string{"traefik-pilot", "/traefik-enterprise/v2.0/"},
+			},
+			golden: "robots-policy.golden.txt",
+		},
+	}
+
+	folders, err := versions.NewFolders("")
+	require.NoError(t, err)
+
+	products, err := Scan("./fixtures/docs", folders)
+	require.NoError(t, err)
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			content := Build(products, test.policy)
+
+			golden := filepath.Join("fixtures", test.golden)
+
+			if os.Getenv("UPDATE_GOLDEN") != "" {
+				errG := os.WriteFile(golden, content, 0o644)
+				require.NoError(t, errG)
+			}
+
+			expected, err := os.ReadFile(golden)
+			require.NoError(t, err)
+
+			assert.Equal(t, string(expected), string(content))
+		})
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	root := t.TempDir()
+
+	for _, p := range []string{"traefik/v1.7", "traefik/v2.0"} {
+		err := os.MkdirAll(filepath.Join(root, p), 0o700)
+		require.NoError(t, err)
+	}
+
+	err := Generate(root, Policy{KeepVersions: 1})
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(filepath.Join(root, fileNameRobots))
+	require.NoError(t, err)
+
+	assert.Contains(t, string(content), "Disallow: /traefik/v1.7/\n")
+	assert.NotContains(t, string(content), "/traefik/v2.0/")
+}
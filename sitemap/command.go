@@ -2,16 +2,21 @@ package sitemap
 
 import (
 	"github.com/ettle/strcase"
+	"github.com/traefik/seo/versions"
 	"github.com/urfave/cli/v2"
 )
 
 const (
-	flagRoot         = "root"
-	flagDebug        = "debug"
-	flagGitUserName  = "git-user-name"
-	flagGitUserEmail = "git-user-email"
-	flagGithubToken  = "token"
-	flagGitBranch    = "git-branch"
+	flagRoot           = "root"
+	flagDebug          = "debug"
+	flagGitUserName    = "git-user-name"
+	flagGitUserEmail   = "git-user-email"
+	flagGithubToken    = "token"
+	flagGitBranch      = "git-branch"
+	flagSince          = "since"
+	flagContentHash    = "content-hash"
+	flagPolicy         = "policy"
+	flagVersionFolders = "version-folders"
 )
 
 // Command is the sitemap command.
@@ -50,6 +55,26 @@ func Command() *cli.Command {
 				EnvVars:  []string{"GITHUB_TOKEN"},
 				Required: true,
 			},
+			&cli.StringFlag{
+				Name:    flagSince,
+				Usage:   "Processes the commits since a date (git date format, e.g. \"48 hours ago\") instead of the commits since the last processed commit.",
+				EnvVars: []string{strcase.ToSNAKE(flagSince)},
+			},
+			&cli.BoolFlag{
+				Name:    flagContentHash,
+				Usage:   "Updates the lastmod of a page only when its content changes (the content hashes are stored in " + fileNameHashes + ").",
+				EnvVars: []string{strcase.ToSNAKE(flagContentHash)},
+			},
+			&cli.PathFlag{
+				Name:    flagPolicy,
+				Usage:   "Path of the YAML file of the priority and change frequency policy.",
+				EnvVars: []string{strcase.ToSNAKE(flagPolicy)},
+			},
+			&cli.StringFlag{
+				Name:    flagVersionFolders,
+				Usage:   "Pattern of the names of the version folders excluded from the sitemaps (default: " + versions.DefaultFolderPattern + ").",
+				EnvVars: []string{strcase.ToSNAKE(flagVersionFolders)},
+			},
 			&cli.StringFlag{
 				Name:    flagGitBranch,
 				Usage:   "The name of the branch to push on it.",
@@ -59,7 +84,15 @@ func Command() *cli.Command {
 			},
 		},
 		Action: func(cliCtx *cli.Context) error {
-			err := Generate(cliCtx.Path(flagRoot))
+			cfg := Config{
+				Root:           cliCtx.Path(flagRoot),
+				Since:          cliCtx.String(flagSince),
+				ContentHash:    cliCtx.Bool(flagContentHash),
+				PolicyFile:     cliCtx.Path(flagPolicy),
+				VersionFolders: cliCtx.String(flagVersionFolders),
+			}
+
+			err := Generate(cfg)
 			if err != nil {
 				return err
 			}
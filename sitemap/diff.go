@@ -3,16 +3,16 @@ package sitemap
 import (
 	"bufio"
 	"bytes"
-	"encoding/xml"
 	"io"
 	"log"
-	"os"
 	"os/exec"
 	"path/filepath"
 	"regexp"
 	"sort"
 	"strings"
 	"time"
+
+	"github.com/traefik/seo/versions"
 )
 
 // Item a sitemap item.
@@ -34,28 +34,40 @@ func NewItem(status, path string, currentDate time.Time) Item {
 }
 
 // FromDiff creates a sitemap from a diff.
-func FromDiff(src string) (URLSet, error) {
-	// Reads existing sitemap.xml file.
-	file, err := os.Open(src)
-	if err != nil {
-		return URLSet{}, err
-	}
+// The diff starts after the last processed commit, or at the since date (git date format) when defined.
+// The pages inside the version folders are excluded.
+func FromDiff(src, since string, policy *Policy, folders *versions.Folders) (URLSet, error) {
+	root := filepath.Dir(src)
 
-	var us URLSet
-	err = xml.NewDecoder(file).Decode(&us)
+	// Reads existing sitemap files.
+	us, err := readSitemap(root, src)
 	if err != nil {
 		return URLSet{}, err
 	}
 
 	// Extract new items.
-	date := time.Now().Add(-48 * time.Hour)
+	filter := "--since=" + since
 
-	data, err := gitLog(filepath.Dir(src), date)
+	if since == "" {
+		hash, errS := readState(root)
+		if errS != nil {
+			return URLSet{}, errS
+		}
+
+		errS = checkReachable(root, hash)
+		if errS != nil {
+			return URLSet{}, errS
+		}
+
+		filter = hash + "..HEAD"
+	}
+
+	data, err := gitLog(root, filter)
 	if err != nil {
 		return URLSet{}, err
 	}
 
-	items, err := extractNewItems(data)
+	items, err := extractNewItems(data, folders)
 	if err != nil {
 		return URLSet{}, err
 	}
@@ -63,19 +75,20 @@ func FromDiff(src string) (URLSet, error) {
 	log.Println("current", len(us.URL), len(items))
 
 	// Merge.
-	set := merge(us, items)
+	set := merge(us, items, policy)
 
 	log.Println("new", len(set.URL), len(items))
 
 	return set, nil
 }
 
-func gitLog(root string, after time.Time) (*bytes.Reader, error) {
+// gitLog returns the changes of the commits selected by the filter (revision range or date option).
+func gitLog(root, filter string) (*bytes.Reader, error) {
 	cmd := exec.Command("git", "log",
 		"--name-status",
 		"--oneline",
 		"--reverse",
-		"--after="+after.Format("2006-01-02"),
+		filter,
 		"--format=%h %cd",
 		"--date=format:%Y-%m-%dT%H:%M:%S",
 	)
@@ -90,11 +103,10 @@ func gitLog(root string, after time.Time) (*bytes.Reader, error) {
 	return bytes.NewReader(output), nil
 }
 
-func extractNewItems(data io.Reader) (map[string]Item, error) {
+func extractNewItems(data io.Reader, folders *versions.Folders) (map[string]Item, error) {
 	expDate := regexp.MustCompile(`^[^\s]+\s+(\d{4}-\d{2}-\d{2}T\d{1,2}:\d{1,2}:\d{1,2})$`)
 	exp := regexp.MustCompile(`^([MADU])\s+([a-z][^/]+.+/)index\.html$`)
 	expSpe := regexp.MustCompile(`^([CR])\d+\s+([a-z][^/]+.+/)index\.html\s+([a-z][^/]+.+/)index\.html$`)
-	expVersion := regexp.MustCompile(`^([^/]+)/(master|v\d+\.\d+)/(?:.+/)?$`)
 
 	uniqStatus := make(map[string]Item)
 
@@ -122,10 +134,10 @@ func extractNewItems(data io.Reader) (map[string]Item, error) {
 		if expSpe.MatchString(line) {
 			submatch := expSpe.FindStringSubmatch(line)
 
-			if !expVersion.MatchString(submatch[2]) {
+			if !isVersioned(folders, submatch[2]) {
 				uniqStatus[baseURL+submatch[2]] = NewItem("D", submatch[2], currentDate)
 			}
-			if !expVersion.MatchString(submatch[3]) {
+			if !isVersioned(folders, submatch[3]) {
 				uniqStatus[baseURL+submatch[3]] = NewItem("A", submatch[3], currentDate)
 			}
 
@@ -138,7 +150,7 @@ func extractNewItems(data io.Reader) (map[string]Item, error) {
 
 		submatch := exp.FindStringSubmatch(line)
 
-		if expVersion.MatchString(submatch[2]) {
+		if isVersioned(folders, submatch[2]) {
 			continue
 		}
 
@@ -148,15 +160,13 @@ func extractNewItems(data io.Reader) (map[string]Item, error) {
 	return uniqStatus, nil
 }
 
-func merge(us URLSet, items map[string]Item) URLSet {
+func merge(us URLSet, items map[string]Item, policy *Policy) URLSet {
 	var smurls []SMUrl
 
 	for _, u := range us.URL {
 		item, ok := items[u.Loc]
 		if !ok {
-			u.ChangeFreq = changeFreqDaily
-
-			smurls = append(smurls, u)
+			smurls = append(smurls, policy.apply(u))
 			continue
 		}
 
@@ -166,11 +176,10 @@ func merge(us URLSet, items map[string]Item) URLSet {
 			continue
 		}
 
-		smurl := SMUrl{
-			Loc:        item.Path,
-			LastMod:    item.Date.Format("2006-01-02"),
-			ChangeFreq: changeFreqDaily,
-		}
+		smurl := policy.apply(SMUrl{
+			Loc:     item.Path,
+			LastMod: item.Date.Format("2006-01-02"),
+		})
 
 		delete(items, u.Loc)
 		smurls = append(smurls, smurl)
@@ -182,11 +191,10 @@ func merge(us URLSet, items map[string]Item) URLSet {
 			continue
 		}
 
-		smurl := SMUrl{
-			Loc:        item.Path,
-			LastMod:    item.Date.Format("2006-01-02"),
-			ChangeFreq: changeFreqDaily,
-		}
+		smurl := policy.apply(SMUrl{
+			Loc:     item.Path,
+			LastMod: item.Date.Format("2006-01-02"),
+		})
 
 		smurls = append(smurls, smurl)
 	}
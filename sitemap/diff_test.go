@@ -8,6 +8,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/traefik/seo/versions"
 )
 
 func Test_extractNewItems(t *testing.T) {
@@ -16,7 +17,10 @@ func Test_extractNewItems(t *testing.T) {
 
 	defer func() { _ = file.Close() }()
 
-	items, err := extractNewItems(file)
+	folders, err := versions.NewFolders("")
+	require.NoError(t, err)
+
+	items, err := extractNewItems(file, folders)
 	require.NoError(t, err)
 
 	if os.Getenv("UPDATE_GOLDEN") != "" {
@@ -58,7 +62,10 @@ func Test_merge(t *testing.T) {
 	err = xml.NewDecoder(file).Decode(&us)
 	require.NoError(t, err)
 
-	set := merge(us, items)
+	policy, err := loadPolicy("")
+	require.NoError(t, err)
+
+	set := merge(us, items, policy)
 
 	if os.Getenv("UPDATE_GOLDEN") != "" {
 		gg, errG := os.Create("./fixtures/sitemap.golden.xml")
@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"regexp"
 	"strings"
 
 	"github.com/ldez/go-git-cmd-wrapper/v2/add"
@@ -18,10 +19,15 @@ import (
 
 const defaultBranch = "master"
 
-const (
-	fileNameSitemap   = "sitemap.xml"
-	fileGZNameSitemap = fileNameSitemap + ".gz"
-)
+const fileNameSitemap = "sitemap.xml"
+
+// pathSpecSitemaps matches the sitemap index and the sitemap files of the products.
+const pathSpecSitemaps = "*sitemap*.xml*"
+
+// pathSpecState matches the state files of the generation (last processed commit, content hashes).
+const pathSpecState = "sitemap.*"
+
+var expSitemapFile = regexp.MustCompile(`(^|[\s/])sitemap(-\d+|-root)?\.xml(\.gz)?$`)
 
 // GitInfo represents the Git user configuration used for commit.
 type GitInfo struct {
@@ -65,7 +71,7 @@ func Commit(cfg GitInfo, debug bool) error {
 	}
 
 	// add target doc path to the index
-	output, err = git.AddWithContext(ctx, add.PathSpec(fileNameSitemap, fileGZNameSitemap), git.Debugger(debug))
+	output, err = git.AddWithContext(ctx, add.PathSpec(pathSpecSitemaps, pathSpecState), git.Debugger(debug))
 	if err != nil {
 		log.Println(output)
 		return fmt.Errorf("failed to add files: %w", err)
@@ -121,7 +127,7 @@ func hasDiff(output string) bool {
 			continue
 		}
 
-		if strings.HasSuffix(line, fileGZNameSitemap) || strings.HasSuffix(line, fileNameSitemap) {
+		if expSitemapFile.MatchString(line) {
 			return true
 		}
 	}
@@ -0,0 +1,137 @@
+package sitemap
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// fileNameHashes is the name of the manifest of the content hashes of the pages.
+const fileNameHashes = "sitemap.hashes.json"
+
+var (
+	// expFingerprint matches the fingerprints of the assets (main.1a2b3c4d.min.js).
+	expFingerprint = regexp.MustCompile(`\.[0-9a-f]{8,}\.`)
+	// expGeneratedDate matches the dates generated by the documentation build.
+	expGeneratedDate = regexp.MustCompile(`\d{4}-\d{2}-\d{2}(?:[T ]\d{2}:\d{2}(?::\d{2})?(?:\.\d+)?(?:Z|[+-]\d{2}:?\d{2})?)?|` +
+		`(?:January|February|March|April|May|June|July|August|September|October|November|December) \d{1,2}, \d{4}`)
+)
+
+// PageHash content hash of a page, and the lastmod of this content.
+type PageHash struct {
+	Hash    string `json:"hash"`
+	LastMod string `json:"lastmod"`
+}
+
+// applyContentHashes keeps the lastmod of the pages whose content is unchanged since the previous generation,
+// and updates the manifest of the content hashes.
+func applyContentHashes(root string, set URLSet) (URLSet, error) {
+	hashes, err := readHashes(root)
+	if err != nil {
+		return URLSet{}, err
+	}
+
+	updated := make(map[string]PageHash)
+
+	for i, u := range set.URL {
+		urlPath := strings.TrimPrefix(u.Loc, baseURL)
+
+		content, errR := os.ReadFile(filepath.Join(root, filepath.FromSlash(urlPath), "index.html"))
+		if errR != nil {
+			if errors.Is(errR, fs.ErrNotExist) {
+				continue
+			}
+
+			return URLSet{}, errR
+		}
+
+		hash, errH := contentHash(content)
+		if errH != nil {
+			return URLSet{}, errH
+		}
+
+		previous, ok := hashes[urlPath]
+		if ok && previous.Hash == hash {
+			set.URL[i].LastMod = previous.LastMod
+		}
+
+		updated[urlPath] = PageHash{Hash: hash, LastMod: set.URL[i].LastMod}
+	}
+
+	err = saveHashes(root, updated)
+	if err != nil {
+		return URLSet{}, err
+	}
+
+	return set, nil
+}
+
+// contentHash returns the hash of the main content of a page,
+// the scripts, the styles, the assets fingerprints, and the generated dates are ignored.
+func contentHash(content []byte) (string, error) {
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(content))
+	if err != nil {
+		return "", err
+	}
+
+	article := doc.Find("article").First()
+	if article.Length() == 0 {
+		article = doc.Find("body").First()
+	}
+
+	article.Find("script, style, noscript, template").Remove()
+
+	var parts []string
+
+	article.Find("img[src], source[src], video[src]").Each(func(_ int, s *goquery.Selection) {
+		src, _ := s.Attr("src")
+		parts = append(parts, expFingerprint.ReplaceAllString(src, "."))
+	})
+
+	parts = append(parts, strings.Join(strings.Fields(article.Text()), " "))
+
+	normalized := expGeneratedDate.ReplaceAllString(strings.Join(parts, "\n"), "")
+
+	sum := sha256.Sum256([]byte(normalized))
+
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// readHashes reads the manifest of the content hashes, indexed by URL path.
+func readHashes(root string) (map[string]PageHash, error) {
+	content, err := os.ReadFile(filepath.Join(root, fileNameHashes))
+	if errors.Is(err, fs.ErrNotExist) {
+		return map[string]PageHash{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	hashes := make(map[string]PageHash)
+
+	err = json.Unmarshal(content, &hashes)
+	if err != nil {
+		return nil, err
+	}
+
+	return hashes, nil
+}
+
+// saveHashes writes the manifest of the content hashes.
+func saveHashes(root string, hashes map[string]PageHash) error {
+	content, err := json.MarshalIndent(hashes, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(root, fileNameHashes), append(content, '\n'), 0o644)
+}
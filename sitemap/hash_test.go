@@ -0,0 +1,161 @@
+package sitemap
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const hashPage = `<html><head><script src="assets/main.1a2b3c4d.min.js"></script></head>
+<body><nav>Home</nav>
+<article>
+<h1>Routing</h1>
+<p>The routers.</p>
+<img src="assets/schema.1a2b3c4d.png" alt="schema">
+<script>var build = "2021-06-01T10:00:00Z";</script>
+<small>Last update: June 1, 2021</small>
+</article>
+</body></html>`
+
+func Test_contentHash(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		page    string
+		changed bool
+	}{
+		{
+			desc: "same page",
+			page: hashPage,
+		},
+		{
+			desc: "asset fingerprints",
+			page: `<html><head><script src="assets/main.9f8e7d6c.min.js"></script></head>
+<body><nav>Home</nav>
+<article>
+<h1>Routing</h1>
+<p>The routers.</p>
+<img src="assets/schema.9f8e7d6c.png" alt="schema">
+<script>var build = "2021-06-01T10:00:00Z";</script>
+<small>Last update: June 1, 2021</small>
+</article>
+</body></html>`,
+		},
+		{
+			desc: "generated dates and scripts",
+			page: `<html><head><script src="assets/main.1a2b3c4d.min.js"></script></head>
+<body><nav>Home</nav>
+<article>
+<h1>Routing</h1>
+<p>The routers.</p>
+<img src="assets/schema.1a2b3c4d.png" alt="schema">
+<script>var build = "2021-07-15T08:00:00Z";</script>
+<small>Last update: July 15, 2021</small>
+</article>
+</body></html>`,
+		},
+		{
+			desc: "outside of the main content",
+			page: `<html><head><script src="assets/main.1a2b3c4d.min.js"></script></head>
+<body><nav>Home Menu</nav>
+<article>
+<h1>Routing</h1>
+<p>The routers.</p>
+<img src="assets/schema.1a2b3c4d.png" alt="schema">
+<script>var build = "2021-06-01T10:00:00Z";</script>
+<small>Last update: June 1, 2021</small>
+</article>
+</body></html>`,
+		},
+		{
+			desc: "text",
+			page: `<html><head><script src="assets/main.1a2b3c4d.min.js"></script></head>
+<body><nav>Home</nav>
+<article>
+<h1>Routing</h1>
+<p>The routers and the services.</p>
+<img src="assets/schema.1a2b3c4d.png" alt="schema">
+<script>var build = "2021-06-01T10:00:00Z";</script>
+<small>Last update: June 1, 2021</small>
+</article>
+</body></html>`,
+			changed: true,
+		},
+		{
+			desc: "image",
+			page: `<html><head><script src="assets/main.1a2b3c4d.min.js"></script></head>
+<body><nav>Home</nav>
+<article>
+<h1>Routing</h1>
+<p>The routers.</p>
+<img src="assets/routers.1a2b3c4d.png" alt="schema">
+<script>var build = "2021-06-01T10:00:00Z";</script>
+<small>Last update: June 1, 2021</small>
+</article>
+</body></html>`,
+			changed: true,
+		},
+	}
+
+	expected, err := contentHash([]byte(hashPage))
+	require.NoError(t, err)
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			hash, err := contentHash([]byte(test.page))
+			require.NoError(t, err)
+
+			assert.Equal(t, test.changed, hash != expected)
+		})
+	}
+}
+
+func Test_applyContentHashes(t *testing.T) {
+	root := t.TempDir()
+
+	page := filepath.Join(root, "traefik", "routing", "index.html")
+	require.NoError(t, os.MkdirAll(filepath.Dir(page), 0o700))
+	require.NoError(t, os.WriteFile(page, []byte(hashPage), 0o600))
+
+	set := func(lastMod string) URLSet {
+		return URLSet{
+			Xmlns: xmlnsSitemap,
+			URL: []SMUrl{
+				{Loc: baseURL + "traefik/routing/", LastMod: lastMod},
+				{Loc: baseURL + "traefik/missing/", LastMod: lastMod},
+			},
+		}
+	}
+
+	// no manifest.
+	us, err := applyContentHashes(root, set("2021-06-01"))
+	require.NoError(t, err)
+
+	assert.Equal(t, set("2021-06-01"), us)
+
+	// rebuilt, same content.
+	us, err = applyContentHashes(root, set("2021-06-15"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "2021-06-01", us.URL[0].LastMod)
+	assert.Equal(t, "2021-06-15", us.URL[1].LastMod)
+
+	// new content.
+	require.NoError(t, os.WriteFile(page, []byte("<html><body><article><p>New content.</p></article></body></html>"), 0o600))
+
+	us, err = applyContentHashes(root, set("2021-07-01"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "2021-07-01", us.URL[0].LastMod)
+
+	hashes, err := readHashes(root)
+	require.NoError(t, err)
+
+	require.Len(t, hashes, 1)
+	assert.Equal(t, "2021-07-01", hashes["traefik/routing/"].LastMod)
+}
@@ -0,0 +1,252 @@
+package sitemap
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const xmlnsSitemap = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+// Protocol limits of a sitemap file.
+const (
+	maxURLs     = 50000
+	maxFileSize = 50 * 1024 * 1024
+)
+
+// fileNameRoot is the name of the sitemap file of the pages that are not in a product folder.
+const fileNameRoot = "sitemap-root.xml"
+
+// expProductSitemap matches the names of the sitemap files of a product.
+// They differ from the sitemap.xml generated by mkdocs in the product folder, which is not read as a previous state.
+var expProductSitemap = regexp.MustCompile(`^sitemap-\d+\.xml(\.gz)?$`)
+
+// Index root of a sitemap index file.
+type Index struct {
+	XMLName xml.Name     `xml:"sitemapindex"`
+	Xmlns   string       `xml:"xmlns,attr"`
+	Sitemap []IndexEntry `xml:"sitemap"`
+}
+
+// IndexEntry item of a sitemap index file.
+type IndexEntry struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// readSitemap reads a sitemap file, or all the sitemap files of a sitemap index.
+func readSitemap(root, src string) (URLSet, error) {
+	content, err := os.ReadFile(src)
+	if err != nil {
+		return URLSet{}, err
+	}
+
+	var probe struct {
+		XMLName xml.Name
+	}
+
+	err = xml.Unmarshal(content, &probe)
+	if err != nil {
+		return URLSet{}, fmt.Errorf("%s: %w", src, err)
+	}
+
+	if probe.XMLName.Local != "sitemapindex" {
+		var us URLSet
+
+		err = xml.Unmarshal(content, &us)
+		if err != nil {
+			return URLSet{}, fmt.Errorf("%s: %w", src, err)
+		}
+
+		return us, nil
+	}
+
+	var index Index
+
+	err = xml.Unmarshal(content, &index)
+	if err != nil {
+		return URLSet{}, fmt.Errorf("%s: %w", src, err)
+	}
+
+	us := URLSet{Xmlns: xmlnsSitemap}
+
+	for _, entry := range index.Sitemap {
+		if !strings.HasPrefix(entry.Loc, baseURL) {
+			return URLSet{}, fmt.Errorf("%s: sitemap outside of the documentation: %s", src, entry.Loc)
+		}
+
+		child, errR := readSitemap(root, filepath.Join(root, filepath.FromSlash(strings.TrimPrefix(entry.Loc, baseURL))))
+		if errR != nil {
+			return URLSet{}, errR
+		}
+
+		us.URL = append(us.URL, child.URL...)
+	}
+
+	sort.Slice(us.URL, func(i, j int) bool {
+		return us.URL[i].Loc < us.URL[j].Loc
+	})
+
+	return us, nil
+}
+
+// saveSitemaps writes one sitemap per product (split over the maximum number of URLs and the maximum size of a file),
+// and the sitemap index referencing them.
+func saveSitemaps(root string, set URLSet, maxCount, maxSize int) error {
+	index := Index{Xmlns: xmlnsSitemap}
+
+	written := make(map[string]struct{})
+
+	for _, group := range groupByProduct(set.URL) {
+		chunks := split(group.urls, maxCount, maxSize)
+
+		for i, chunk := range chunks {
+			name := sitemapFileName(group.product, i)
+
+			err := saveSitemap(filepath.Join(root, filepath.FromSlash(name)), URLSet{Xmlns: xmlnsSitemap, URL: chunk})
+			if err != nil {
+				return err
+			}
+
+			written[name] = struct{}{}
+
+			index.Sitemap = append(index.Sitemap, IndexEntry{Loc: baseURL + name, LastMod: lastMod(chunk)})
+		}
+
+		err := removeStaleSitemaps(root, group.product, written)
+		if err != nil {
+			return err
+		}
+	}
+
+	return saveSitemap(filepath.Join(root, fileNameSitemap), index)
+}
+
+type productURLs struct {
+	product string
+	urls    []SMUrl
+}
+
+// groupByProduct groups the URLs by product (the first folder of the URL path), sorted by product.
+func groupByProduct(urls []SMUrl) []productURLs {
+	groups := make(map[string][]SMUrl)
+
+	for _, u := range urls {
+		product, _, found := strings.Cut(strings.TrimPrefix(u.Loc, baseURL), "/")
+		if !found {
+			product = ""
+		}
+
+		groups[product] = append(groups[product], u)
+	}
+
+	var products []productURLs
+	for product, group := range groups {
+		products = append(products, productURLs{product: product, urls: group})
+	}
+
+	sort.Slice(products, func(i, j int) bool {
+		return products[i].product < products[j].product
+	})
+
+	return products
+}
+
+// split splits the URLs into chunks respecting the maximum number of URLs and the maximum size of a sitemap file.
+func split(urls []SMUrl, maxCount, maxSize int) [][]SMUrl {
+	overhead := len(xml.Header) + len(`<urlset xmlns="`+xmlnsSitemap+`">`) + len("</urlset>") + 2
+
+	var (
+		chunks [][]SMUrl
+		chunk  []SMUrl
+		size   = overhead
+	)
+
+	for _, u := range urls {
+		s := urlSize(u)
+
+		if len(chunk) > 0 && (len(chunk) >= maxCount || size+s > maxSize) {
+			chunks = append(chunks, chunk)
+			chunk = nil
+			size = overhead
+		}
+
+		chunk = append(chunk, u)
+		size += s
+	}
+
+	if len(chunk) > 0 || len(chunks) == 0 {
+		chunks = append(chunks, chunk)
+	}
+
+	return chunks
+}
+
+// urlSize returns the size of a URL in a sitemap file.
+func urlSize(u SMUrl) int {
+	data, err := xml.MarshalIndent(struct {
+		XMLName xml.Name `xml:"url"`
+		SMUrl
+	}{SMUrl: u}, "  ", "  ")
+	if err != nil {
+		return 0
+	}
+
+	return len(data) + 1
+}
+
+// sitemapFileName returns the path of the i-th sitemap file of a product, relative to the documentation root.
+func sitemapFileName(product string, i int) string {
+	if product == "" {
+		return fileNameRoot
+	}
+
+	return fmt.Sprintf("%s/sitemap-%d.xml", product, i+1)
+}
+
+// lastMod returns the most recent modification date of the URLs.
+func lastMod(urls []SMUrl) string {
+	var last string
+
+	for _, u := range urls {
+		if u.LastMod > last {
+			last = u.LastMod
+		}
+	}
+
+	return last
+}
+
+// removeStaleSitemaps removes the sitemap files of a product that are not written anymore.
+func removeStaleSitemaps(root, product string, written map[string]struct{}) error {
+	if product == "" {
+		return nil
+	}
+
+	entries, err := os.ReadDir(filepath.Join(root, product))
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !expProductSitemap.MatchString(name) {
+			continue
+		}
+
+		if _, ok := written[product+"/"+strings.TrimSuffix(name, ".gz")]; ok {
+			continue
+		}
+
+		err = os.Remove(filepath.Join(root, product, name))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
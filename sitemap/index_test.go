@@ -0,0 +1,206 @@
+package sitemap
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_split(t *testing.T) {
+	var urls []SMUrl
+	for i := 0; i < 5; i++ {
+		urls = append(urls, SMUrl{Loc: fmt.Sprintf("%straefik/v2.%d/index.html", baseURL, i), LastMod: "2021-06-01"})
+	}
+
+	testCases := []struct {
+		desc     string
+		urls     []SMUrl
+		maxCount int
+		maxSize  int
+		expected []int
+	}{
+		{
+			desc:     "under the limits",
+			urls:     urls,
+			maxCount: 10,
+			maxSize:  maxFileSize,
+			expected: []int{5},
+		},
+		{
+			desc:     "over the URL limit",
+			urls:     urls,
+			maxCount: 2,
+			maxSize:  maxFileSize,
+			expected: []int{2, 2, 1},
+		},
+		{
+			desc:     "over the size limit",
+			urls:     urls,
+			maxCount: 10,
+			maxSize:  500,
+			expected: []int{3, 2},
+		},
+		{
+			desc:     "no URL",
+			maxCount: 10,
+			maxSize:  maxFileSize,
+			expected: []int{0},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			chunks := split(test.urls, test.maxCount, test.maxSize)
+
+			var sizes []int
+			for _, chunk := range chunks {
+				sizes = append(sizes, len(chunk))
+			}
+
+			assert.Equal(t, test.expected, sizes)
+		})
+	}
+}
+
+func Test_groupByProduct(t *testing.T) {
+	urls := []SMUrl{
+		{Loc: baseURL + "traefik/index.html"},
+		{Loc: baseURL + "index.html"},
+		{Loc: baseURL + "traefik-mesh/index.html"},
+		{Loc: baseURL + "traefik/v2.4/index.html"},
+	}
+
+	groups := groupByProduct(urls)
+
+	expected := []productURLs{
+		{product: "", urls: []SMUrl{{Loc: baseURL + "index.html"}}},
+		{product: "traefik", urls: []SMUrl{{Loc: baseURL + "traefik/index.html"}, {Loc: baseURL + "traefik/v2.4/index.html"}}},
+		{product: "traefik-mesh", urls: []SMUrl{{Loc: baseURL + "traefik-mesh/index.html"}}},
+	}
+
+	assert.Equal(t, expected, groups)
+}
+
+func Test_sitemapFileName(t *testing.T) {
+	testCases := []struct {
+		product  string
+		i        int
+		expected string
+	}{
+		{product: "", i: 0, expected: "sitemap-root.xml"},
+		{product: "traefik", i: 0, expected: "traefik/sitemap-1.xml"},
+		{product: "traefik", i: 1, expected: "traefik/sitemap-2.xml"},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.expected, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, sitemapFileName(test.product, test.i))
+		})
+	}
+}
+
+func Test_saveSitemaps(t *testing.T) {
+	root := t.TempDir()
+
+	for _, product := range []string{"traefik", "traefik-mesh"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(root, product), 0o700))
+	}
+
+	// stale split sitemap from a previous generation.
+	require.NoError(t, os.WriteFile(filepath.Join(root, "traefik", "sitemap-3.xml"), nil, 0o600))
+	// sitemap generated by mkdocs, not managed by the generation.
+	require.NoError(t, os.WriteFile(filepath.Join(root, "traefik-mesh", "sitemap.xml"), nil, 0o600))
+
+	set := URLSet{
+		Xmlns: xmlnsSitemap,
+		URL: []SMUrl{
+			{Loc: baseURL + "index.html", LastMod: "2021-06-01"},
+			{Loc: baseURL + "traefik-mesh/index.html", LastMod: "2021-06-02"},
+			{Loc: baseURL + "traefik/index.html", LastMod: "2021-06-03"},
+			{Loc: baseURL + "traefik/v2.4/index.html", LastMod: "2021-06-04"},
+			{Loc: baseURL + "traefik/v2.5/index.html", LastMod: "2021-06-05"},
+		},
+	}
+
+	err := saveSitemaps(root, set, 2, maxFileSize)
+	require.NoError(t, err)
+
+	for _, name := range []string{"sitemap.xml", "sitemap-root.xml", "traefik/sitemap-1.xml", "traefik/sitemap-2.xml", "traefik-mesh/sitemap-1.xml"} {
+		assert.FileExists(t, filepath.Join(root, filepath.FromSlash(name)))
+		assert.FileExists(t, filepath.Join(root, filepath.FromSlash(name)+".gz"))
+	}
+
+	assert.NoFileExists(t, filepath.Join(root, "traefik", "sitemap-3.xml"))
+	assert.FileExists(t, filepath.Join(root, "traefik-mesh", "sitemap.xml"))
+
+	index, err := os.ReadFile(filepath.Join(root, "sitemap.xml"))
+	require.NoError(t, err)
+
+	if os.Getenv("UPDATE_GOLDEN") != "" {
+		errG := os.WriteFile("./fixtures/index.golden.xml", index, 0o600)
+		require.NoError(t, errG)
+	}
+
+	golden, err := os.ReadFile("./fixtures/index.golden.xml")
+	require.NoError(t, err)
+
+	assert.Equal(t, string(golden), string(index))
+
+	us, err := readSitemap(root, filepath.Join(root, "sitemap.xml"))
+	require.NoError(t, err)
+
+	assert.Equal(t, set, us)
+}
+
+func Test_readSitemap_urlSet(t *testing.T) {
+	us, err := readSitemap("./fixtures", "./fixtures/sitemap-5dd7f130.xml")
+	require.NoError(t, err)
+
+	assert.NotEmpty(t, us.URL)
+}
+
+func Test_readSitemap_missingFile(t *testing.T) {
+	root := t.TempDir()
+
+	err := saveSitemap(filepath.Join(root, "sitemap.xml"), Index{
+		Xmlns:   xmlnsSitemap,
+		Sitemap: []IndexEntry{{Loc: baseURL + "traefik/sitemap.xml"}},
+	})
+	require.NoError(t, err)
+
+	_, err = readSitemap(root, filepath.Join(root, "sitemap.xml"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func Test_hasDiff(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		output   string
+		expected bool
+	}{
+		{desc: "index", output: " M sitemap.xml\n", expected: true},
+		{desc: "product sitemap", output: " M traefik/sitemap-2.xml.gz\n", expected: true},
+		{desc: "root sitemap", output: "?? sitemap-root.xml\n", expected: true},
+		{desc: "other files", output: " M traefik/index.html\n", expected: false},
+		{desc: "empty", output: "", expected: false},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, hasDiff(test.output))
+		})
+	}
+}
@@ -0,0 +1,246 @@
+package sitemap
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Change frequencies of the sitemap protocol.
+const (
+	changeFreqAlways  = "always"
+	changeFreqHourly  = "hourly"
+	changeFreqDaily   = "daily"
+	changeFreqWeekly  = "weekly"
+	changeFreqMonthly = "monthly"
+	changeFreqYearly  = "yearly"
+	changeFreqNever   = "never"
+)
+
+// Priority priority of a URL, from 0.0 to 1.0.
+type Priority float64
+
+// MarshalText formats the priority as a decimal number (0.5, 1.0).
+func (p Priority) MarshalText() ([]byte, error) {
+	text := strconv.FormatFloat(float64(p), 'f', -1, 64)
+	if !strings.Contains(text, ".") {
+		text += ".0"
+	}
+
+	return []byte(text), nil
+}
+
+// Policy assigns the priority and the change frequency of the URLs.
+type Policy struct {
+	// Default is applied to the URLs not matched by a rule, and completes the matching rule.
+	Default Rule `yaml:"default"`
+	// Rules are evaluated in order, the first matching rule is applied.
+	Rules []Rule `yaml:"rules"`
+}
+
+// Rule is a priority and change frequency rule.
+// The criteria (Match, Product, MinDepth, MaxDepth) are combined, an empty criterion matches all the URLs.
+type Rule struct {
+	// Match is a glob matched against the URL path (traefik/migration/v2/),
+	// "*" matches a path segment, "**" matches several path segments.
+	Match string `yaml:"match"`
+	// Product is the name of the product (first folder of the URL path).
+	Product string `yaml:"product"`
+	// MinDepth is the minimum number of path segments (traefik/routing/ is 2), zero disables the criterion.
+	MinDepth int `yaml:"minDepth"`
+	// MaxDepth is the maximum number of path segments, zero disables the criterion.
+	MaxDepth int `yaml:"maxDepth"`
+	// Priority is the priority of the URLs, from 0.0 to 1.0.
+	Priority *float64 `yaml:"priority"`
+	// ChangeFreq is the change frequency of the URLs (always, hourly, daily, weekly, monthly, yearly, never).
+	ChangeFreq string `yaml:"changeFreq"`
+
+	exp *regexp.Regexp
+}
+
+// defaultPolicy is the built-in policy of the documentation.
+func defaultPolicy() *Policy {
+	return &Policy{
+		Default: Rule{Priority: float(0.5), ChangeFreq: changeFreqDaily},
+		Rules: []Rule{
+			// home page of a product.
+			{MaxDepth: 1, Priority: float(1.0)},
+			// migration guides.
+			{Match: "*/migration/**", Priority: float(0.3), ChangeFreq: changeFreqMonthly},
+			{Match: "**/migrating-*/", Priority: float(0.3), ChangeFreq: changeFreqMonthly},
+			// reference pages.
+			{Match: "*/reference/**", Priority: float(0.4), ChangeFreq: changeFreqWeekly},
+			{Match: "*/references/**", Priority: float(0.4), ChangeFreq: changeFreqWeekly},
+		},
+	}
+}
+
+// loadPolicy reads a policy file, the built-in policy is used if the filename is empty.
+func loadPolicy(filename string) (*Policy, error) {
+	if filename == "" {
+		policy := defaultPolicy()
+
+		return policy, policy.compile()
+	}
+
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = file.Close() }()
+
+	decoder := yaml.NewDecoder(file)
+	decoder.KnownFields(true)
+
+	policy := &Policy{}
+
+	err = decoder.Decode(policy)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the policy file %s: %w", filename, err)
+	}
+
+	err = policy.compile()
+	if err != nil {
+		return nil, fmt.Errorf("invalid policy file %s: %w", filename, err)
+	}
+
+	return policy, nil
+}
+
+// compile validates the rules and compiles their globs.
+func (p *Policy) compile() error {
+	if p.Default.Match != "" || p.Default.Product != "" || p.Default.MinDepth != 0 || p.Default.MaxDepth != 0 {
+		return fmt.Errorf("the default rule must not have criteria")
+	}
+
+	err := p.Default.validate()
+	if err != nil {
+		return fmt.Errorf("default rule: %w", err)
+	}
+
+	for i := range p.Rules {
+		rule := &p.Rules[i]
+
+		err = rule.validate()
+		if err != nil {
+			return fmt.Errorf("rule %d: %w", i, err)
+		}
+
+		if rule.Match != "" {
+			rule.exp = compileGlob(rule.Match)
+		}
+	}
+
+	return nil
+}
+
+// apply sets the priority and the change frequency of a URL.
+func (p *Policy) apply(u SMUrl) SMUrl {
+	urlPath := strings.TrimPrefix(u.Loc, baseURL)
+
+	rule := p.Default
+
+	for _, r := range p.Rules {
+		if r.matches(urlPath) {
+			rule = r
+			break
+		}
+	}
+
+	u.ChangeFreq = rule.ChangeFreq
+	if u.ChangeFreq == "" {
+		u.ChangeFreq = p.Default.ChangeFreq
+	}
+
+	u.Priority = nil
+
+	switch {
+	case rule.Priority != nil:
+		u.Priority = priority(*rule.Priority)
+	case p.Default.Priority != nil:
+		u.Priority = priority(*p.Default.Priority)
+	}
+
+	return u
+}
+
+func (r Rule) validate() error {
+	if r.Priority != nil && (*r.Priority < 0 || *r.Priority > 1) {
+		return fmt.Errorf("priority must be between 0.0 and 1.0: %v", *r.Priority)
+	}
+
+	switch r.ChangeFreq {
+	case "", changeFreqAlways, changeFreqHourly, changeFreqDaily, changeFreqWeekly, changeFreqMonthly, changeFreqYearly, changeFreqNever:
+	default:
+		return fmt.Errorf("invalid change frequency: %s", r.ChangeFreq)
+	}
+
+	if r.MaxDepth != 0 && r.MinDepth > r.MaxDepth {
+		return fmt.Errorf("minDepth (%d) greater than maxDepth (%d)", r.MinDepth, r.MaxDepth)
+	}
+
+	return nil
+}
+
+func (r Rule) matches(urlPath string) bool {
+	if r.exp != nil && !r.exp.MatchString(urlPath) {
+		return false
+	}
+
+	if r.Product != "" && !strings.HasPrefix(urlPath, r.Product+"/") {
+		return false
+	}
+
+	depth := pathDepth(urlPath)
+
+	return depth >= r.MinDepth && (r.MaxDepth == 0 || depth <= r.MaxDepth)
+}
+
+// pathDepth returns the number of segments of a URL path.
+func pathDepth(urlPath string) int {
+	urlPath = strings.Trim(urlPath, "/")
+	if urlPath == "" {
+		return 0
+	}
+
+	return strings.Count(urlPath, "/") + 1
+}
+
+// compileGlob converts a glob to a regular expression ("*" matches a path segment, "**" matches several path segments).
+func compileGlob(glob string) *regexp.Regexp {
+	var exp strings.Builder
+
+	exp.WriteString("^")
+
+	for i := 0; i < len(glob); i++ {
+		switch {
+		case strings.HasPrefix(glob[i:], "**"):
+			exp.WriteString(".*")
+			i++
+		case glob[i] == '*':
+			exp.WriteString("[^/]*")
+		case glob[i] == '?':
+			exp.WriteString("[^/]")
+		default:
+			exp.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		}
+	}
+
+	exp.WriteString("$")
+
+	return regexp.MustCompile(exp.String())
+}
+
+func float(v float64) *float64 {
+	return &v
+}
+
+func priority(v float64) *Priority {
+	p := Priority(v)
+	return &p
+}
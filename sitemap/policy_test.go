@@ -0,0 +1,198 @@
+package sitemap
+
+import (
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicy_apply(t *testing.T) {
+	defaultP, err := loadPolicy("")
+	require.NoError(t, err)
+
+	fileP, err := loadPolicy("./fixtures/policy.yml")
+	require.NoError(t, err)
+
+	zeroP := &Policy{
+		Default: Rule{ChangeFreq: changeFreqDaily},
+		Rules:   []Rule{{Product: "traefik-pilot", Priority: float(0)}},
+	}
+	require.NoError(t, zeroP.compile())
+
+	testCases := []struct {
+		desc     string
+		policy   *Policy
+		urlPath  string
+		expected SMUrl
+	}{
+		{
+			desc:     "default policy: product home page",
+			policy:   defaultP,
+			urlPath:  "traefik/",
+			expected: SMUrl{ChangeFreq: changeFreqDaily, Priority: priority(1)},
+		},
+		{
+			desc:     "default policy: page",
+			policy:   defaultP,
+			urlPath:  "traefik/routing/overview/",
+			expected: SMUrl{ChangeFreq: changeFreqDaily, Priority: priority(0.5)},
+		},
+		{
+			desc:     "default policy: migration guide",
+			policy:   defaultP,
+			urlPath:  "traefik/migration/v1-to-v2/",
+			expected: SMUrl{ChangeFreq: changeFreqMonthly, Priority: priority(0.3)},
+		},
+		{
+			desc:     "default policy: reference page",
+			policy:   defaultP,
+			urlPath:  "traefik-enterprise/references/cli/teectl/",
+			expected: SMUrl{ChangeFreq: changeFreqWeekly, Priority: priority(0.4)},
+		},
+		{
+			desc:     "product rule",
+			policy:   fileP,
+			urlPath:  "traefik-pilot/",
+			expected: SMUrl{ChangeFreq: changeFreqYearly, Priority: priority(0.1)},
+		},
+		{
+			desc:     "rule completed by the default rule",
+			policy:   fileP,
+			urlPath:  "traefik/",
+			expected: SMUrl{ChangeFreq: changeFreqWeekly, Priority: priority(1)},
+		},
+		{
+			desc:     "depth rule",
+			policy:   fileP,
+			urlPath:  "traefik/migration/v1-to-v2/",
+			expected: SMUrl{ChangeFreq: changeFreqMonthly, Priority: priority(0.6)},
+		},
+		{
+			desc:     "depth rule not matching",
+			policy:   fileP,
+			urlPath:  "traefik/migration/",
+			expected: SMUrl{ChangeFreq: changeFreqWeekly, Priority: priority(0.6)},
+		},
+		{
+			desc:     "zero priority",
+			policy:   zeroP,
+			urlPath:  "traefik-pilot/",
+			expected: SMUrl{ChangeFreq: changeFreqDaily, Priority: priority(0)},
+		},
+		{
+			desc:     "no priority",
+			policy:   zeroP,
+			urlPath:  "traefik/",
+			expected: SMUrl{ChangeFreq: changeFreqDaily},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			u := test.policy.apply(SMUrl{Loc: baseURL + test.urlPath, LastMod: "2021-06-01", ChangeFreq: changeFreqNever, Priority: priority(0.9)})
+
+			test.expected.Loc = baseURL + test.urlPath
+			test.expected.LastMod = "2021-06-01"
+
+			assert.Equal(t, test.expected, u)
+		})
+	}
+}
+
+func Test_loadPolicy_invalid(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		content string
+	}{
+		{
+			desc:    "priority out of range",
+			content: "rules:\n  - match: \"*/\"\n    priority: 1.5\n",
+		},
+		{
+			desc:    "unknown change frequency",
+			content: "default:\n  changeFreq: often\n",
+		},
+		{
+			desc:    "default rule with criteria",
+			content: "default:\n  product: traefik\n",
+		},
+		{
+			desc:    "depth range",
+			content: "rules:\n  - minDepth: 3\n    maxDepth: 2\n",
+		},
+		{
+			desc:    "unknown field",
+			content: "rules:\n  - glob: \"*/\"\n",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			filename := filepath.Join(t.TempDir(), "policy.yml")
+			require.NoError(t, os.WriteFile(filename, []byte(test.content), 0o600))
+
+			_, err := loadPolicy(filename)
+			require.Error(t, err)
+		})
+	}
+}
+
+func Test_compileGlob(t *testing.T) {
+	testCases := []struct {
+		glob     string
+		urlPath  string
+		expected bool
+	}{
+		{glob: "*/", urlPath: "traefik/", expected: true},
+		{glob: "*/", urlPath: "traefik/routing/", expected: false},
+		{glob: "*/migration/**", urlPath: "traefik/migration/v1-to-v2/", expected: true},
+		{glob: "*/migration/**", urlPath: "traefik/routing/migration/", expected: false},
+		{glob: "**/migration/**", urlPath: "traefik/routing/migration/", expected: true},
+		{glob: "traefik/v?.?/", urlPath: "traefik/v2.4/", expected: true},
+		{glob: "traefik/v?.?/", urlPath: "traefik/v2/4/", expected: false},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.glob+" "+test.urlPath, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, compileGlob(test.glob).MatchString(test.urlPath))
+		})
+	}
+}
+
+func TestPriority_MarshalText(t *testing.T) {
+	data, err := xml.Marshal(SMUrl{Loc: baseURL, Priority: priority(1)})
+	require.NoError(t, err)
+
+	assert.Contains(t, string(data), "<priority>1.0</priority>")
+
+	// the lowest priority is kept.
+	data, err = xml.Marshal(SMUrl{Loc: baseURL, Priority: priority(0)})
+	require.NoError(t, err)
+
+	assert.Contains(t, string(data), "<priority>0.0</priority>")
+
+	// no priority.
+	data, err = xml.Marshal(SMUrl{Loc: baseURL})
+	require.NoError(t, err)
+
+	assert.NotContains(t, string(data), "<priority>")
+
+	var u SMUrl
+	err = xml.Unmarshal([]byte("<url><loc>"+baseURL+"</loc><priority>0.8</priority></url>"), &u)
+	require.NoError(t, err)
+
+	assert.Equal(t, priority(0.8), u.Priority)
+}
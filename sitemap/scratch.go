@@ -1,21 +1,34 @@
 package sitemap
 
 import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
 	"io/fs"
+	"log"
+	"os/exec"
 	"path/filepath"
 	"regexp"
 	"sort"
 	"strings"
 	"time"
+
+	"github.com/traefik/seo/versions"
 )
 
-// FromScratch creates a sitemap from scratch.
-func FromScratch(root string) (URLSet, error) {
+// FromScratch creates a sitemap from scratch, the pages inside the version folders are excluded.
+func FromScratch(root string, policy *Policy, folders *versions.Folders) (URLSet, error) {
 	exp := regexp.MustCompile(`^(.+/)index\.html$`)
-	expVersion := regexp.MustCompile(`^([^/]+)/(master|v\d+\.\d+)/.*$`)
 
 	us := URLSet{
-		Xmlns: "http://www.sitemaps.org/schemas/sitemap/0.9",
+		Xmlns: xmlnsSitemap,
+	}
+
+	dates, err := lastCommitDates(root)
+	if err != nil {
+		// the modification times of the files are used instead.
+		log.Println("Unable to read the commit dates:", err)
 	}
 
 	errW := filepath.WalkDir(root, func(path string, info fs.DirEntry, err error) error {
@@ -35,17 +48,27 @@ func FromScratch(root string) (URLSet, error) {
 			return nil
 		}
 
-		urlPath := strings.TrimPrefix(strings.TrimSuffix(path, "index.html"), root)
+		rel, errR := filepath.Rel(root, path)
+		if errR != nil {
+			return errR
+		}
+
+		relPath := filepath.ToSlash(rel)
+		urlPath := strings.TrimSuffix(relPath, "index.html")
 
-		if expVersion.MatchString(urlPath) {
+		if isVersioned(folders, urlPath) {
 			return nil
 		}
 
-		us.URL = append(us.URL, SMUrl{
-			Loc:        baseURL + urlPath,
-			LastMod:    time.Now().Format("2006-01-02"),
-			ChangeFreq: changeFreqDaily,
-		})
+		date, errD := lastModDate(relPath, info, dates)
+		if errD != nil {
+			return errD
+		}
+
+		us.URL = append(us.URL, policy.apply(SMUrl{
+			Loc:     baseURL + urlPath,
+			LastMod: date.Format("2006-01-02"),
+		}))
 
 		return nil
 	})
@@ -59,3 +82,71 @@ func FromScratch(root string) (URLSet, error) {
 
 	return us, nil
 }
+
+// lastModDate returns the date of the last commit of a file, or its modification time when the file is untracked.
+func lastModDate(rel string, info fs.DirEntry, dates map[string]time.Time) (time.Time, error) {
+	if date, ok := dates[rel]; ok {
+		return date, nil
+	}
+
+	fi, err := info.Info()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return fi.ModTime(), nil
+}
+
+// lastCommitDates returns the date of the last commit of each index.html file, the paths are relative to the root.
+// The whole history is read once.
+func lastCommitDates(root string) (map[string]time.Time, error) {
+	cmd := exec.Command("git", "-c", "core.quotePath=false", "log",
+		"--name-only",
+		"--relative",
+		"--format=%x00%cI",
+		"--",
+		"*index.html",
+	)
+
+	cmd.Dir = root
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the git history of %s: %w", root, err)
+	}
+
+	return parseCommitDates(bytes.NewReader(output))
+}
+
+// parseCommitDates parses the output of git log (newest commits first),
+// a commit is a date line (prefixed by NUL) followed by the names of the files.
+func parseCommitDates(data io.Reader) (map[string]time.Time, error) {
+	dates := make(map[string]time.Time)
+
+	var currentDate time.Time
+
+	scanner := bufio.NewScanner(data)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "\x00") {
+			d, err := time.Parse(time.RFC3339, strings.TrimPrefix(line, "\x00"))
+			if err != nil {
+				return nil, err
+			}
+
+			currentDate = d
+
+			continue
+		}
+
+		if _, ok := dates[line]; !ok {
+			dates[line] = currentDate
+		}
+	}
+
+	return dates, scanner.Err()
+}
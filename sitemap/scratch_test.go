@@ -0,0 +1,146 @@
+package sitemap
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/seo/versions"
+)
+
+func TestFromScratch(t *testing.T) {
+	root := t.TempDir()
+
+	writeFile(t, root, "traefik/index.html")
+	writeFile(t, root, "traefik/v2.4/index.html")
+	writeFile(t, root, "traefik/routing/index.html")
+
+	gitCmd(t, root, "2021-06-01T10:00:00", "init", "-q")
+	gitCmd(t, root, "2021-06-01T10:00:00", "add", "-A")
+	gitCmd(t, root, "2021-06-01T10:00:00", "commit", "-q", "-m", "init")
+
+	writeFile(t, root, "traefik/routing/index.html")
+	gitCmd(t, root, "2021-06-15T10:00:00", "commit", "-q", "-a", "-m", "routing")
+
+	untracked := writeFile(t, root, "traefik/middlewares/index.html")
+	mtime := time.Date(2021, 7, 1, 10, 0, 0, 0, time.UTC)
+	require.NoError(t, os.Chtimes(untracked, mtime, mtime))
+
+	policy, err := loadPolicy("")
+	require.NoError(t, err)
+
+	folders, err := versions.NewFolders("")
+	require.NoError(t, err)
+
+	us, err := FromScratch(root, policy, folders)
+	require.NoError(t, err)
+
+	expected := []SMUrl{
+		{Loc: baseURL + "traefik/", LastMod: "2021-06-01", ChangeFreq: changeFreqDaily, Priority: priority(1)},
+		{Loc: baseURL + "traefik/middlewares/", LastMod: "2021-07-01", ChangeFreq: changeFreqDaily, Priority: priority(0.5)},
+		{Loc: baseURL + "traefik/routing/", LastMod: "2021-06-15", ChangeFreq: changeFreqDaily, Priority: priority(0.5)},
+	}
+
+	assert.Equal(t, expected, us.URL)
+}
+
+func TestFromScratch_noGit(t *testing.T) {
+	root := t.TempDir()
+
+	page := writeFile(t, root, "traefik/index.html")
+	mtime := time.Date(2021, 7, 1, 10, 0, 0, 0, time.UTC)
+	require.NoError(t, os.Chtimes(page, mtime, mtime))
+
+	policy, err := loadPolicy("")
+	require.NoError(t, err)
+
+	folders, err := versions.NewFolders("")
+	require.NoError(t, err)
+
+	// the git history is not readable: the modification times are used.
+	us, err := FromScratch(root, policy, folders)
+	require.NoError(t, err)
+
+	assert.Equal(t, []SMUrl{{Loc: baseURL + "traefik/", LastMod: "2021-07-01", ChangeFreq: changeFreqDaily, Priority: priority(1)}}, us.URL)
+}
+
+func TestFromScratch_versionFolders(t *testing.T) {
+	root := t.TempDir()
+
+	writeFile(t, root, "traefik/index.html")
+	writeFile(t, root, "traefik/version-2.1/index.html")
+	writeFile(t, root, "traefik/2.1/index.html")
+
+	gitCmd(t, root, "2021-06-01T10:00:00", "init", "-q")
+	gitCmd(t, root, "2021-06-01T10:00:00", "add", "-A")
+	gitCmd(t, root, "2021-06-01T10:00:00", "commit", "-q", "-m", "init")
+
+	policy, err := loadPolicy("")
+	require.NoError(t, err)
+
+	folders, err := versions.NewFolders(`^version-(?P<version>\d+\.\d+)$`)
+	require.NoError(t, err)
+
+	us, err := FromScratch(root, policy, folders)
+	require.NoError(t, err)
+
+	expected := []SMUrl{
+		{Loc: baseURL + "traefik/", LastMod: "2021-06-01", ChangeFreq: changeFreqDaily, Priority: priority(1)},
+		{Loc: baseURL + "traefik/2.1/", LastMod: "2021-06-01", ChangeFreq: changeFreqDaily, Priority: priority(0.5)},
+	}
+
+	assert.Equal(t, expected, us.URL)
+}
+
+func Test_parseCommitDates(t *testing.T) {
+	data := "\x002021-06-15T23:30:00-05:00\n\ntraefik/routing/index.html\n\n\x002021-06-01T10:00:00Z\n\ntraefik/index.html\ntraefik/routing/index.html\n"
+
+	dates, err := parseCommitDates(strings.NewReader(data))
+	require.NoError(t, err)
+
+	expected := map[string]time.Time{
+		"traefik/index.html":         time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC),
+		"traefik/routing/index.html": time.Date(2021, 6, 15, 23, 30, 0, 0, time.FixedZone("", -5*60*60)),
+	}
+
+	require.Len(t, dates, len(expected))
+
+	for name, date := range expected {
+		assert.Truef(t, date.Equal(dates[name]), "%s: %s != %s", name, date, dates[name])
+	}
+
+	// the date of the commit is kept, in the time zone of the commit.
+	assert.Equal(t, "2021-06-15", dates["traefik/routing/index.html"].Format("2006-01-02"))
+}
+
+func Test_parseCommitDates_invalidDate(t *testing.T) {
+	_, err := parseCommitDates(strings.NewReader("\x002021-06-15T10:00:00\n"))
+	require.Error(t, err)
+}
+
+func writeFile(t *testing.T, root, name string) string {
+	t.Helper()
+
+	path := filepath.Join(root, filepath.FromSlash(name))
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o700))
+	require.NoError(t, os.WriteFile(path, []byte(time.Now().String()), 0o600))
+
+	return path
+}
+
+func gitCmd(t *testing.T, dir, date string, args ...string) {
+	t.Helper()
+
+	cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_AUTHOR_DATE="+date, "GIT_COMMITTER_DATE="+date)
+
+	output, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(output))
+}
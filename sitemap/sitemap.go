@@ -3,15 +3,21 @@ package sitemap
 import (
 	"compress/gzip"
 	"encoding/xml"
+	"errors"
 	"io"
+	"io/fs"
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
+
+	"github.com/traefik/seo/versions"
 )
 
 const baseURL = "https://doc.traefik.io/"
 
-const changeFreqDaily = "daily"
+// URL is the URL of the sitemap file produced by Generate.
+const URL = baseURL + fileNameSitemap
 
 // URLSet root of a sitemap file.
 type URLSet struct {
@@ -22,37 +28,93 @@ type URLSet struct {
 
 // SMUrl item of a sitemap file.
 type SMUrl struct {
-	Loc        string `xml:"loc"`
-	LastMod    string `xml:"lastmod"`
-	ChangeFreq string `xml:"changefreq,omitempty"`
-	Priority   int    `xml:"priority,omitempty"`
+	Loc        string    `xml:"loc"`
+	LastMod    string    `xml:"lastmod"`
+	ChangeFreq string    `xml:"changefreq,omitempty"`
+	Priority   *Priority `xml:"priority,omitempty"`
+}
+
+// isVersioned returns true if a URL path (product/version/...) is inside a version folder of a product.
+func isVersioned(folders *versions.Folders, urlPath string) bool {
+	parts := strings.SplitN(urlPath, "/", 3)
+
+	return len(parts) == 3 && folders.IsVersion(parts[1])
 }
 
-// Generate generates sitemap files.
-func Generate(root string) error {
-	src := filepath.Join(root, "sitemap.xml")
+// Config the sitemap generation configuration.
+type Config struct {
+	// Root is the path to the root of the documentation.
+	Root string
+	// Since is a date (git date format) overriding the last processed commit.
+	Since string
+	// ContentHash keeps the lastmod of a page while the hash of its content is unchanged.
+	ContentHash bool
+	// PolicyFile is the path of the priority and change frequency policy, the built-in policy is used if empty.
+	PolicyFile string
+	// VersionFolders matches the names of the version folders excluded from the sitemaps,
+	// versions.DefaultFolderPattern is used if empty.
+	VersionFolders string
+}
+
+// Generate generates sitemap files: one sitemap per product, and a sitemap index at the root of the documentation.
+func Generate(cfg Config) error {
+	root := cfg.Root
+	src := filepath.Join(root, fileNameSitemap)
+
+	policy, err := loadPolicy(cfg.PolicyFile)
+	if err != nil {
+		return err
+	}
+
+	folders, err := versions.NewFolders(cfg.VersionFolders)
+	if err != nil {
+		return err
+	}
+
+	head, err := headCommit(root)
+	if err != nil {
+		return err
+	}
 
 	var set URLSet
-	if _, err := os.Stat(src); err != nil {
+	if _, errS := os.Stat(src); errS != nil {
 		log.Println("From scratch", src)
 
-		set, err = FromScratch(root)
+		set, err = FromScratch(root, policy, folders)
 		if err != nil {
 			return err
 		}
 	} else {
 		log.Println("From diff", src)
 
-		set, err = FromDiff(src)
+		set, err = FromDiff(src, cfg.Since, policy, folders)
+		if errors.Is(err, fs.ErrNotExist) || errors.Is(err, errUnreachableCommit) {
+			log.Println("From scratch:", err)
+
+			set, err = FromScratch(root, policy, folders)
+		}
 		if err != nil {
 			return err
 		}
 	}
 
-	return saveSitemap(src, set)
+	if cfg.ContentHash {
+		set, err = applyContentHashes(root, set)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = saveSitemaps(root, set, maxURLs, maxFileSize)
+	if err != nil {
+		return err
+	}
+
+	return saveState(root, head)
 }
 
-func saveSitemap(dst string, set URLSet) error {
+// saveSitemap writes a sitemap document (URLSet or Index), and its gzipped copy.
+func saveSitemap(dst string, doc interface{}) error {
 	file, err := os.Create(dst)
 	if err != nil {
 		return err
@@ -65,10 +127,10 @@ func saveSitemap(dst string, set URLSet) error {
 		return err
 	}
 
-	defer func() { _ = file.Close() }()
+	defer func() { _ = gz.Close() }()
 
 	zw := gzip.NewWriter(gz)
-	zw.Name = "sitemap.xml"
+	zw.Name = filepath.Base(dst)
 
 	defer func() { _ = zw.Close() }()
 
@@ -86,7 +148,7 @@ func saveSitemap(dst string, set URLSet) error {
 
 	encoder := xml.NewEncoder(output)
 	encoder.Indent("", "  ")
-	err = encoder.Encode(set)
+	err = encoder.Encode(doc)
 	if err != nil {
 		return err
 	}
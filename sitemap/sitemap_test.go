@@ -0,0 +1,111 @@
+package sitemap
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/seo/versions"
+)
+
+func TestGenerate(t *testing.T) {
+	root := t.TempDir()
+
+	writeFile(t, root, "traefik/index.html")
+	writeFile(t, root, "traefik/routing/index.html")
+
+	gitCmd(t, root, "2021-06-01T10:00:00", "init", "-q")
+	gitCmd(t, root, "2021-06-01T10:00:00", "add", "-A")
+	gitCmd(t, root, "2021-06-01T10:00:00", "commit", "-q", "-m", "init")
+
+	// from scratch.
+	err := Generate(Config{Root: root})
+	require.NoError(t, err)
+
+	assertState(t, root)
+	assertSitemap(t, root, []SMUrl{
+		{Loc: baseURL + "traefik/", LastMod: "2021-06-01", ChangeFreq: changeFreqDaily, Priority: priority(1)},
+		{Loc: baseURL + "traefik/routing/", LastMod: "2021-06-01", ChangeFreq: changeFreqDaily, Priority: priority(0.5)},
+	})
+
+	gitCmd(t, root, "2021-06-02T10:00:00", "add", "-A")
+	gitCmd(t, root, "2021-06-02T10:00:00", "commit", "-q", "-m", "sitemap")
+
+	writeFile(t, root, "traefik/middlewares/index.html")
+	gitCmd(t, root, "2021-06-15T10:00:00", "add", "-A")
+	gitCmd(t, root, "2021-06-15T10:00:00", "commit", "-q", "-m", "middlewares")
+
+	// from the last processed commit.
+	err = Generate(Config{Root: root})
+	require.NoError(t, err)
+
+	expected := []SMUrl{
+		{Loc: baseURL + "traefik/", LastMod: "2021-06-01", ChangeFreq: changeFreqDaily, Priority: priority(1)},
+		{Loc: baseURL + "traefik/middlewares/", LastMod: "2021-06-15", ChangeFreq: changeFreqDaily, Priority: priority(0.5)},
+		{Loc: baseURL + "traefik/routing/", LastMod: "2021-06-01", ChangeFreq: changeFreqDaily, Priority: priority(0.5)},
+	}
+
+	assertState(t, root)
+	assertSitemap(t, root, expected)
+
+	// unreachable commit: full rebuild.
+	err = saveState(root, "0123456789abcdef0123456789abcdef01234567")
+	require.NoError(t, err)
+
+	err = Generate(Config{Root: root})
+	require.NoError(t, err)
+
+	assertState(t, root)
+	assertSitemap(t, root, expected)
+}
+
+func TestFromDiff_since(t *testing.T) {
+	root := t.TempDir()
+
+	writeFile(t, root, "traefik/index.html")
+
+	gitCmd(t, root, "2021-06-01T10:00:00", "init", "-q")
+	gitCmd(t, root, "2021-06-01T10:00:00", "add", "-A")
+	gitCmd(t, root, "2021-06-01T10:00:00", "commit", "-q", "-m", "init")
+
+	err := saveSitemaps(root, URLSet{Xmlns: xmlnsSitemap}, maxURLs, maxFileSize)
+	require.NoError(t, err)
+
+	policy, err := loadPolicy("")
+	require.NoError(t, err)
+
+	folders, err := versions.NewFolders("")
+	require.NoError(t, err)
+
+	// no state file: the since date is used.
+	us, err := FromDiff(filepath.Join(root, fileNameSitemap), "2021-05-01", policy, folders)
+	require.NoError(t, err)
+
+	assert.Equal(t, []SMUrl{{Loc: baseURL + "traefik/", LastMod: "2021-06-01", ChangeFreq: changeFreqDaily, Priority: priority(1)}}, us.URL)
+
+	_, err = FromDiff(filepath.Join(root, fileNameSitemap), "", policy, folders)
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func assertState(t *testing.T, root string) {
+	t.Helper()
+
+	head, err := headCommit(root)
+	require.NoError(t, err)
+
+	hash, err := readState(root)
+	require.NoError(t, err)
+
+	assert.Equal(t, head, hash)
+}
+
+func assertSitemap(t *testing.T, root string, expected []SMUrl) {
+	t.Helper()
+
+	us, err := readSitemap(root, filepath.Join(root, fileNameSitemap))
+	require.NoError(t, err)
+
+	assert.Equal(t, expected, us.URL)
+}
@@ -0,0 +1,90 @@
+package sitemap
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// fileNameState is the name of the file containing the last commit processed by the sitemap generation.
+const fileNameState = "sitemap.commit"
+
+// errUnreachableCommit is returned when the last processed commit is not in the history of HEAD anymore.
+var errUnreachableCommit = errors.New("unreachable commit")
+
+// readState reads the last processed commit.
+func readState(root string) (string, error) {
+	content, err := os.ReadFile(filepath.Join(root, fileNameState))
+	if err != nil {
+		return "", err
+	}
+
+	hash := strings.TrimSpace(string(content))
+	if hash == "" {
+		return "", fmt.Errorf("%s: empty file: %w", fileNameState, os.ErrNotExist)
+	}
+
+	return hash, nil
+}
+
+// saveState writes the last processed commit.
+func saveState(root, hash string) error {
+	return os.WriteFile(filepath.Join(root, fileNameState), []byte(hash+"\n"), 0o644)
+}
+
+// headCommit returns the hash of the HEAD commit.
+func headCommit(root string) (string, error) {
+	cmd := exec.Command("git", "rev-parse", "HEAD")
+	cmd.Dir = root
+
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("unable to get the HEAD commit: %w", err)
+	}
+
+	return string(bytes.TrimSpace(output)), nil
+}
+
+// checkReachable checks that a commit is an ancestor of HEAD (a force push or a history rewrite can remove it).
+// It returns errUnreachableCommit if the commit is missing or is not an ancestor of HEAD, the other git errors as-is.
+func checkReachable(root, hash string) error {
+	ok, err := gitTest(root, "rev-parse", "--verify", "--quiet", hash+"^{commit}")
+	if err != nil {
+		return fmt.Errorf("unable to find the commit %s: %w", hash, err)
+	}
+
+	if ok {
+		ok, err = gitTest(root, "merge-base", "--is-ancestor", hash, "HEAD")
+		if err != nil {
+			return fmt.Errorf("unable to check the ancestry of the commit %s: %w", hash, err)
+		}
+	}
+
+	if !ok {
+		return fmt.Errorf("%s: %w", hash, errUnreachableCommit)
+	}
+
+	return nil
+}
+
+// gitTest runs a git command answering a question with its exit code: 0 is true, 1 is false, the other codes are errors.
+func gitTest(root string, args ...string) (bool, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = root
+
+	output, err := cmd.CombinedOutput()
+	if err == nil {
+		return true, nil
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return false, nil
+	}
+
+	return false, fmt.Errorf("%w: %s", err, bytes.TrimSpace(output))
+}
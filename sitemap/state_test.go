@@ -0,0 +1,80 @@
+package sitemap
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_checkReachable(t *testing.T) {
+	root := t.TempDir()
+
+	writeFile(t, root, "traefik/index.html")
+	gitCmd(t, root, "2021-06-01T10:00:00", "init", "-q")
+	gitCmd(t, root, "2021-06-01T10:00:00", "add", "-A")
+	gitCmd(t, root, "2021-06-01T10:00:00", "commit", "-q", "-m", "init")
+
+	first, err := headCommit(root)
+	require.NoError(t, err)
+
+	writeFile(t, root, "traefik/index.html")
+	gitCmd(t, root, "2021-06-02T10:00:00", "commit", "-q", "-a", "-m", "rewritten")
+
+	rewritten, err := headCommit(root)
+	require.NoError(t, err)
+
+	// the history is rewritten: the second commit is not an ancestor of HEAD anymore.
+	gitCmd(t, root, "2021-06-03T10:00:00", "reset", "-q", "--hard", first)
+
+	testCases := []struct {
+		desc        string
+		root        string
+		hash        string
+		unreachable bool
+		wantErr     bool
+	}{
+		{
+			desc: "ancestor",
+			root: root,
+			hash: first,
+		},
+		{
+			desc:        "not an ancestor",
+			root:        root,
+			hash:        rewritten,
+			unreachable: true,
+			wantErr:     true,
+		},
+		{
+			desc:        "missing commit",
+			root:        root,
+			hash:        "0123456789abcdef0123456789abcdef01234567",
+			unreachable: true,
+			wantErr:     true,
+		},
+		{
+			desc:    "not a git repository",
+			root:    t.TempDir(),
+			hash:    first,
+			wantErr: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			err := checkReachable(test.root, test.hash)
+			if !test.wantErr {
+				require.NoError(t, err)
+				return
+			}
+
+			require.Error(t, err)
+			assert.Equal(t, test.unreachable, errors.Is(err, errUnreachableCommit))
+		})
+	}
+}
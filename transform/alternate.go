@@ -0,0 +1,151 @@
+package transform
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// AlternateTransform links the versioned pages to the same page in the other versions of the documentation.
+// The links are consumed by the search engines and by the version picker:
+//
+//	<link rel="alternate" href="https://doc.traefik.io/traefik/v2.0/foo/" data-version="v2.0" />
+type AlternateTransform struct {
+	*versionedPages
+
+	product string
+	cfg     ProductConfig
+}
+
+// latestVersion is the version of the unversioned root folder, which holds the latest documentation.
+const latestVersion = "latest"
+
+// alternateLink is a link to the same page in a version.
+type alternateLink struct {
+	version string
+	href    string
+}
+
+// NewAlternateTransform creates a new AlternateTransform.
+func NewAlternateTransform(product string, cfg ProductConfig) (*AlternateTransform, error) {
+	pages, err := newVersionedPages(product, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return newAlternateTransform(product, cfg, pages), nil
+}
+
+func newAlternateTransform(product string, cfg ProductConfig, pages *versionedPages) *AlternateTransform {
+	return &AlternateTransform{versionedPages: pages, product: product, cfg: cfg}
+}
+
+// Plan computes the alternate links of a page without writing them.
+func (t *AlternateTransform) Plan(filename string, content []byte) (Result, error) {
+	m, err := t.page(filename)
+	if err != nil {
+		return Result{}, err
+	}
+
+	expected, err := t.alternateLinks(m.Root, m.RelPath)
+	if err != nil {
+		return Result{}, err
+	}
+
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(content))
+	if err != nil {
+		return Result{}, err
+	}
+
+	head := doc.Find("head").First()
+	links := head.Find(`link[rel="alternate"][data-version]`)
+
+	var current []alternateLink
+
+	links.Each(func(_ int, s *goquery.Selection) {
+		current = append(current, alternateLink{version: s.AttrOr("data-version", ""), href: s.AttrOr("href", "")})
+	})
+
+	if equalLinks(current, expected) {
+		return Result{Path: filename, Version: m.Version, Content: content}, nil
+	}
+
+	links.Remove()
+
+	for _, link := range expected {
+		head.AppendHtml(fmt.Sprintf(`<link rel="alternate" href=%q data-version=%q />`, link.href, link.version))
+	}
+
+	change := Change{Kind: kindAlternate, Action: actionUpdate, From: linkVersions(current), To: linkVersions(expected)}
+	if len(current) == 0 {
+		change.Action = actionAdd
+	}
+
+	newContent, err := renderDocument(doc)
+	if err != nil {
+		return Result{}, err
+	}
+
+	return Result{Path: filename, Version: m.Version, Changes: []Change{change}, Content: newContent}, nil
+}
+
+// alternateLinks returns the links to the versions containing the page, from the latest to the oldest version.
+func (t *AlternateTransform) alternateLinks(root, relPath string) ([]alternateLink, error) {
+	base, err := url.Parse(t.cfg.BaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse the root URL: %s: %w", t.cfg.BaseURL, err)
+	}
+
+	var links []alternateLink
+
+	if fileExists(root, relPath) {
+		href, errU := pageURL(base, t.product, relPath)
+		if errU != nil {
+			return nil, fmt.Errorf("unable to create the alternate URL: %s %s: %w", t.cfg.BaseURL, relPath, errU)
+		}
+
+		links = append(links, alternateLink{version: latestVersion, href: href})
+	}
+
+	for _, folder := range t.folders.sorted(root) {
+		if !fileExists(root, path.Join(folder.Folder, relPath)) {
+			continue
+		}
+
+		href, errU := pageURL(base, path.Join(t.product, folder.Folder), relPath)
+		if errU != nil {
+			return nil, fmt.Errorf("unable to create the alternate URL: %s %s %s: %w", t.cfg.BaseURL, folder.Folder, relPath, errU)
+		}
+
+		links = append(links, alternateLink{version: folder.Version, href: href})
+	}
+
+	return links, nil
+}
+
+func equalLinks(a, b []alternateLink) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func linkVersions(links []alternateLink) string {
+	var versions []string
+	for _, link := range links {
+		versions = append(versions, link.version)
+	}
+
+	return strings.Join(versions, ", ")
+}
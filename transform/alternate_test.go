@@ -0,0 +1,106 @@
+package transform
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlternateTransform_Plan(t *testing.T) {
+	root := t.TempDir()
+
+	for _, p := range []string{"foo/index.html", "v1.0/foo/index.html", "v1.0/bar/index.html", "v2.0/foo/index.html", "v1.10/foo/index.html", "v1.10/bar/index.html"} {
+		err := os.MkdirAll(filepath.Join(root, filepath.Dir(p)), 0o700)
+		require.NoError(t, err)
+
+		err = os.WriteFile(filepath.Join(root, p), nil, 0o600)
+		require.NoError(t, err)
+	}
+
+	transform, err := NewAlternateTransform("test", Config{}.productConfig("test"))
+	require.NoError(t, err)
+
+	scanner := versionScanner{pages: transform.versionedPages}
+	for _, p := range []string{"v1.0/foo/index.html", "v1.0/bar/index.html", "v2.0/foo/index.html", "v1.10/foo/index.html", "v1.10/bar/index.html"} {
+		err = scanner.Scan(filepath.Join(root, p))
+		require.NoError(t, err)
+	}
+
+	scanner.EndScan()
+
+	testCases := []struct {
+		desc     string
+		path     string
+		content  string
+		expected []Change
+		links    map[string]string
+	}{
+		{
+			desc:     "add links",
+			path:     "v1.0/foo/index.html",
+			content:  `<html><head></head><body></body></html>`,
+			expected: []Change{{Kind: kindAlternate, Action: actionAdd, To: "latest, v2.0, v1.10, v1.0"}},
+			links: map[string]string{
+				"latest": "https://doc.traefik.io/test/foo/",
+				"v2.0":   "https://doc.traefik.io/test/v2.0/foo/",
+				"v1.10":  "https://doc.traefik.io/test/v1.10/foo/",
+				"v1.0":   "https://doc.traefik.io/test/v1.0/foo/",
+			},
+		},
+		{
+			desc:     "page missing in a version",
+			path:     "v1.0/bar/index.html",
+			content:  `<html><head></head><body></body></html>`,
+			expected: []Change{{Kind: kindAlternate, Action: actionAdd, To: "v1.10, v1.0"}},
+			links: map[string]string{
+				"v1.10": "https://doc.traefik.io/test/v1.10/bar/",
+				"v1.0":  "https://doc.traefik.io/test/v1.0/bar/",
+			},
+		},
+		{
+			desc:     "update links",
+			path:     "v1.10/bar/index.html",
+			content:  `<html><head><link rel="alternate" href="https://doc.traefik.io/test/v1.0/bar/" data-version="v1.0"/><link rel="alternate" href="/feed.xml"/></head><body></body></html>`,
+			expected: []Change{{Kind: kindAlternate, Action: actionUpdate, From: "v1.0", To: "v1.10, v1.0"}},
+			links: map[string]string{
+				"v1.10": "https://doc.traefik.io/test/v1.10/bar/",
+				"v1.0":  "https://doc.traefik.io/test/v1.0/bar/",
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			file := filepath.Join(root, test.path)
+
+			res, err := transform.Plan(file, []byte(test.content))
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, res.Changes)
+
+			doc, err := goquery.NewDocumentFromReader(bytes.NewReader(res.Content))
+			require.NoError(t, err)
+
+			links := make(map[string]string)
+			doc.Find(`link[rel="alternate"][data-version]`).Each(func(_ int, s *goquery.Selection) {
+				links[s.AttrOr("data-version", "")] = s.AttrOr("href", "")
+			})
+
+			assert.Equal(t, test.links, links)
+
+			// The links are stable.
+			res, err = transform.Plan(file, res.Content)
+			require.NoError(t, err)
+
+			assert.Empty(t, res.Changes)
+		})
+	}
+}
@@ -0,0 +1,214 @@
+package transform
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// rewriteRule rewrites the relative path of a page to the path of its canonical page.
+type rewriteRule struct {
+	exp         *regexp.Regexp
+	replacement string
+}
+
+// Canonical resolution strategies.
+const (
+	strategySamePath = "same-path"
+	strategyRewrite  = "rewrite"
+	strategyAncestor = "ancestor"
+	strategyRedirect = "redirect"
+)
+
+// fallbackAncestor is the canonical fallback using the nearest existing parent page.
+const fallbackAncestor = "ancestor"
+
+// reasonIncorrectKept explains a missing canonical page when an incorrect canonical link is kept in replace mode.
+const reasonIncorrectKept = "incorrect canonical kept"
+
+// resolveCanonical returns the path of the canonical page, relative to the latest documentation root,
+// and the strategy used to find it.
+// The candidates are, in order, the results of the matching rewrite rules and the path of the page itself,
+// then, with the ancestor fallback, the index pages of the parent folders.
+// The rewrite rules come first: a page moved in the latest documentation (traefik middlewares) can still exist at its old path.
+// The redirections of each candidate are followed (redirect map and redirect stub pages).
+// The first candidate that exists in the latest documentation is used.
+func (t PageTransform) resolveCanonical(root, relPath string) (string, string, bool) {
+	type candidate struct {
+		path     string
+		strategy string
+	}
+
+	var candidates []candidate
+
+	for _, rw := range t.rewrites {
+		if rw.exp.MatchString(relPath) {
+			candidates = append(candidates, candidate{path: rw.exp.ReplaceAllString(relPath, rw.replacement), strategy: strategyRewrite})
+		}
+	}
+
+	candidates = append(candidates, candidate{path: relPath, strategy: strategySamePath})
+
+	if t.cfg.CanonicalFallback == fallbackAncestor {
+		for _, p := range ancestors(relPath) {
+			candidates = append(candidates, candidate{path: p, strategy: strategyAncestor})
+		}
+	}
+
+	for _, c := range candidates {
+		target, followed, ok := t.redirects.follow(root, c.path)
+		if !ok {
+			continue
+		}
+
+		if followed {
+			c.path = target
+			c.strategy = strategyRedirect
+		}
+
+		if fileExists(root, c.path) {
+			return c.path, c.strategy, true
+		}
+	}
+
+	return "", "", false
+}
+
+// ancestors returns the index pages of the parent folders of a page, from the nearest to the documentation root.
+// (a/b/c/index.html -> a/b/index.html, a/index.html, index.html).
+func ancestors(relPath string) []string {
+	dir := path.Dir(relPath)
+
+	if path.Base(relPath) == "index.html" {
+		if dir == "." {
+			return nil
+		}
+
+		dir = path.Dir(dir)
+	}
+
+	var pages []string
+
+	for {
+		pages = append(pages, path.Join(dir, "index.html"))
+
+		if dir == "." {
+			return pages
+		}
+
+		dir = path.Dir(dir)
+	}
+}
+
+// setCanonical adds the canonical link of a page, or replaces an incorrect one in replace mode.
+// It returns the change, or false if the page is unchanged.
+func (t PageTransform) setCanonical(s *goquery.Selection, fp string) (Change, bool) {
+	link := s.Find(`link[rel="canonical"]`)
+	if link == nil {
+		return Change{}, false
+	}
+
+	if len(link.Nodes) != 0 && !t.cfg.ReplaceCanonical {
+		return Change{}, false
+	}
+
+	r, err := url.Parse(t.cfg.BaseURL)
+	if err != nil {
+		log.Printf("ERROR: unable to parse the root URL: %s", t.cfg.BaseURL)
+		return Change{}, false
+	}
+
+	href, err := pageURL(r, t.product, fp)
+	if err != nil {
+		log.Printf("ERROR: unable to create canonical path: %s %s %s", t.cfg.BaseURL, t.product, fp)
+		return Change{}, false
+	}
+
+	if len(link.Nodes) == 0 {
+		s.AppendHtml(fmt.Sprintf(`<link rel="canonical" href=%q />`, href))
+
+		return Change{Kind: kindCanonical, Action: actionAdd, To: href}, true
+	}
+
+	current := link.First().AttrOr("href", "")
+	if current == href || !t.isIncorrectCanonical(r, current) {
+		return Change{}, false
+	}
+
+	link.Slice(1, link.Length()).Remove()
+	link.First().SetAttr("href", href)
+
+	return Change{Kind: kindCanonical, Action: actionReplace, From: current, To: href}, true
+}
+
+// missingCanonical returns the change reporting a page without canonical page:
+// the page has no canonical link, or, in replace mode, its canonical link is incorrect but can't be replaced.
+func (t PageTransform) missingCanonical(s *goquery.Selection, relPath string) (Change, bool) {
+	change := Change{Kind: kindCanonical, Action: actionMissing, From: relPath}
+
+	link := s.Find(`link[rel="canonical"]`)
+	if link.Length() == 0 {
+		return change, true
+	}
+
+	if !t.cfg.ReplaceCanonical {
+		return Change{}, false
+	}
+
+	r, err := url.Parse(t.cfg.BaseURL)
+	if err != nil {
+		log.Printf("ERROR: unable to parse the root URL: %s", t.cfg.BaseURL)
+		return Change{}, false
+	}
+
+	if !t.isIncorrectCanonical(r, link.First().AttrOr("href", "")) {
+		return Change{}, false
+	}
+
+	change.Reason = reasonIncorrectKept
+
+	return change, true
+}
+
+// isIncorrectCanonical returns true if a canonical URL points at another host or inside a versioned folder.
+func (t PageTransform) isIncorrectCanonical(base *url.URL, href string) bool {
+	u, err := base.Parse(href)
+	if err != nil {
+		return true
+	}
+
+	if !strings.EqualFold(u.Host, base.Host) {
+		return true
+	}
+
+	p := u.Path
+	if !strings.HasSuffix(p, ".html") {
+		p = strings.TrimSuffix(p, "/") + "/index.html"
+	}
+
+	return t.pattern.MatchString(p)
+}
+
+// pageURL returns the URL of a page: the URL of its folder, with a trailing slash.
+func pageURL(base *url.URL, product, relPath string) (string, error) {
+	u, err := base.Parse(path.Join(product, filepath.Dir(relPath), "/"))
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSuffix(u.String(), "/") + "/", nil
+}
+
+// fileExists returns true if the page exists in the documentation.
+func fileExists(root, relPath string) bool {
+	_, err := os.Stat(filepath.Join(root, relPath))
+
+	return err == nil
+}
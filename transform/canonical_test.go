@@ -0,0 +1,296 @@
+package transform
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPageTransform_resolveCanonical(t *testing.T) {
+	root := t.TempDir()
+
+	for _, p := range []string{"index.html", "foo/index.html", "features/bar/index.html", "fallback/bar/index.html", "a/index.html",
+		"middlewares/foo/index.html", "middlewares/http/foo/index.html"} {
+		err := os.MkdirAll(filepath.Join(root, filepath.Dir(p)), 0o700)
+		require.NoError(t, err)
+
+		err = os.WriteFile(filepath.Join(root, p), nil, 0o600)
+		require.NoError(t, err)
+	}
+
+	transform, err := NewPageTransform("test", ProductConfig{
+		VersionPattern: defaultVersionPattern,
+		Rewrites: []Rewrite{
+			{Pattern: `^foo/(.+)$`, Replacement: "moved/$1"},
+			{Pattern: `^bar/(.+)$`, Replacement: "nope/bar/$1"},
+			{Pattern: `^bar/(.+)$`, Replacement: "features/bar/$1"},
+			{Pattern: `^bar/(.+)$`, Replacement: "fallback/bar/$1"},
+			{Pattern: `^middlewares/([^/]+/[^/]+\.html)$`, Replacement: "middlewares/http/$1"},
+		},
+		CanonicalFallback: fallbackAncestor,
+	})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		desc     string
+		relPath  string
+		expected string
+		strategy string
+		found    bool
+	}{
+		{
+			desc:     "same path",
+			relPath:  "index.html",
+			expected: "index.html",
+			strategy: strategySamePath,
+			found:    true,
+		},
+		{
+			desc:     "same path when the rule targets are missing",
+			relPath:  "foo/index.html",
+			expected: "foo/index.html",
+			strategy: strategySamePath,
+			found:    true,
+		},
+		{
+			desc:     "rule before same path",
+			relPath:  "middlewares/foo/index.html",
+			expected: "middlewares/http/foo/index.html",
+			strategy: strategyRewrite,
+			found:    true,
+		},
+		{
+			desc:     "first rule with an existing target",
+			relPath:  "bar/index.html",
+			expected: "features/bar/index.html",
+			strategy: strategyRewrite,
+			found:    true,
+		},
+		{
+			desc:     "nearest ancestor",
+			relPath:  "a/b/c/index.html",
+			expected: "a/index.html",
+			strategy: strategyAncestor,
+			found:    true,
+		},
+		{
+			desc:     "documentation root",
+			relPath:  "baz/index.html",
+			expected: "index.html",
+			strategy: strategyAncestor,
+			found:    true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			relPath, strategy, found := transform.resolveCanonical(root, test.relPath)
+
+			assert.Equal(t, test.found, found)
+			assert.Equal(t, test.expected, relPath)
+			assert.Equal(t, test.strategy, strategy)
+		})
+	}
+}
+
+func TestPageTransform_resolveCanonical_withoutFallback(t *testing.T) {
+	root := t.TempDir()
+
+	err := os.WriteFile(filepath.Join(root, "index.html"), nil, 0o600)
+	require.NoError(t, err)
+
+	relPath, strategy, found := testPageTransform(t, "test").resolveCanonical(root, "foo/index.html")
+
+	assert.False(t, found)
+	assert.Empty(t, relPath)
+	assert.Empty(t, strategy)
+}
+
+func Test_ancestors(t *testing.T) {
+	testCases := []struct {
+		relPath  string
+		expected []string
+	}{
+		{
+			relPath: "index.html",
+		},
+		{
+			relPath:  "a/index.html",
+			expected: []string{"index.html"},
+		},
+		{
+			relPath:  "a/b/c/index.html",
+			expected: []string{"a/b/index.html", "a/index.html", "index.html"},
+		},
+		{
+			relPath:  "a/b/page.html",
+			expected: []string{"a/b/index.html", "a/index.html", "index.html"},
+		},
+		{
+			relPath:  "page.html",
+			expected: []string{"index.html"},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.relPath, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, ancestors(test.relPath))
+		})
+	}
+}
+
+func TestPageTransform_Plan_missingCanonical(t *testing.T) {
+	root := t.TempDir()
+
+	file := copyFile(t, "foo/index.html", "v1.0", root)
+
+	res, err := planFile(file, []fileTransform{testPageTransform(t, "test")})
+	require.NoError(t, err)
+
+	assert.Contains(t, res.Changes, Change{Kind: kindCanonical, Action: actionMissing, From: "foo/index.html"})
+	assert.NotContains(t, string(res.Content), `rel="canonical"`)
+}
+
+func TestPageTransform_Plan_existingCanonical(t *testing.T) {
+	root := t.TempDir()
+
+	file := filepath.Join(root, "v1.0", "foo", "index.html")
+	require.NoError(t, os.MkdirAll(filepath.Dir(file), 0o700))
+
+	content := []byte(`<html><head><link rel="canonical" href="https://doc.traefik.io/test/bar/"/></head><body></body></html>`)
+	require.NoError(t, os.WriteFile(file, content, 0o600))
+
+	res, err := planFile(file, []fileTransform{testPageTransform(t, "test")})
+	require.NoError(t, err)
+
+	for _, change := range res.Changes {
+		assert.NotEqual(t, kindCanonical, change.Kind)
+	}
+}
+
+func TestPageTransform_Plan_incorrectCanonicalKept(t *testing.T) {
+	root := t.TempDir()
+
+	file := filepath.Join(root, "v1.0", "foo", "index.html")
+	require.NoError(t, os.MkdirAll(filepath.Dir(file), 0o700))
+
+	content := []byte(`<html><head><link rel="canonical" href="https://doc.traefik.io/test/v1.0/foo/"/></head><body></body></html>`)
+	require.NoError(t, os.WriteFile(file, content, 0o600))
+
+	cfg := Config{}.productConfig("test")
+	cfg.ReplaceCanonical = true
+
+	transform, err := NewPageTransform("test", cfg)
+	require.NoError(t, err)
+
+	// the latest documentation has no foo page: the canonical link pointing inside the version folder is kept.
+	res, err := planFile(file, []fileTransform{transform})
+	require.NoError(t, err)
+
+	assert.Contains(t, res.Changes, Change{Kind: kindCanonical, Action: actionMissing, From: "foo/index.html", Reason: reasonIncorrectKept})
+	assert.Contains(t, string(res.Content), `href="https://doc.traefik.io/test/v1.0/foo/"`)
+
+	var totals Totals
+	totals.add(res)
+
+	assert.Equal(t, 1, totals.CanonicalMissing)
+}
+
+func TestPageTransform_setCanonical(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		replace  bool
+		head     string
+		expected Change
+		changed  bool
+		links    []string
+	}{
+		{
+			desc:     "add",
+			head:     `<title>Test</title>`,
+			expected: Change{Kind: kindCanonical, Action: actionAdd, To: "https://doc.traefik.io/test/foo/"},
+			changed:  true,
+			links:    []string{"https://doc.traefik.io/test/foo/"},
+		},
+		{
+			desc:  "existing without replace mode",
+			head:  `<link rel="canonical" href="https://doc.traefik.io/test/v1.0/foo/">`,
+			links: []string{"https://doc.traefik.io/test/v1.0/foo/"},
+		},
+		{
+			desc:     "versioned folder",
+			replace:  true,
+			head:     `<link rel="canonical" href="https://doc.traefik.io/test/v1.0/foo/">`,
+			expected: Change{Kind: kindCanonical, Action: actionReplace, From: "https://doc.traefik.io/test/v1.0/foo/", To: "https://doc.traefik.io/test/foo/"},
+			changed:  true,
+			links:    []string{"https://doc.traefik.io/test/foo/"},
+		},
+		{
+			desc:     "relative versioned folder",
+			replace:  true,
+			head:     `<link rel="canonical" href="/test/v1.0/foo/page.html">`,
+			expected: Change{Kind: kindCanonical, Action: actionReplace, From: "/test/v1.0/foo/page.html", To: "https://doc.traefik.io/test/foo/"},
+			changed:  true,
+			links:    []string{"https://doc.traefik.io/test/foo/"},
+		},
+		{
+			desc:     "wrong host",
+			replace:  true,
+			head:     `<link rel="canonical" href="https://example.com/test/foo/"><link rel="canonical" href="https://example.com/">`,
+			expected: Change{Kind: kindCanonical, Action: actionReplace, From: "https://example.com/test/foo/", To: "https://doc.traefik.io/test/foo/"},
+			changed:  true,
+			links:    []string{"https://doc.traefik.io/test/foo/"},
+		},
+		{
+			desc:    "other latest page",
+			replace: true,
+			head:    `<link rel="canonical" href="https://doc.traefik.io/test/bar/">`,
+			links:   []string{"https://doc.traefik.io/test/bar/"},
+		},
+		{
+			desc:    "already correct",
+			replace: true,
+			head:    `<link rel="canonical" href="https://doc.traefik.io/test/foo/">`,
+			links:   []string{"https://doc.traefik.io/test/foo/"},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := Config{}.productConfig("test")
+			cfg.ReplaceCanonical = test.replace
+
+			transform, err := NewPageTransform("test", cfg)
+			require.NoError(t, err)
+
+			doc, err := goquery.NewDocumentFromReader(strings.NewReader("<html><head>" + test.head + "</head></html>"))
+			require.NoError(t, err)
+
+			change, changed := transform.setCanonical(doc.Find("head"), "foo/index.html")
+
+			assert.Equal(t, test.changed, changed)
+			assert.Equal(t, test.expected, change)
+
+			var links []string
+			doc.Find(`link[rel="canonical"]`).Each(func(_ int, s *goquery.Selection) {
+				links = append(links, s.AttrOr("href", ""))
+			})
+
+			assert.Equal(t, test.links, links)
+		})
+	}
+}
@@ -1,23 +1,302 @@
 package transform
 
-import "github.com/urfave/cli/v2"
+import (
+	"fmt"
+	"os"
+	"unicode/utf8"
+
+	"github.com/urfave/cli/v2"
+	"gopkg.in/yaml.v3"
+)
 
 // Transform flag names.
 const (
-	FlagPath    = "path"
-	FlagProduct = "product"
+	FlagPath             = "path"
+	FlagProduct          = "product"
+	FlagDryRun           = "dry-run"
+	FlagDiff             = "diff"
+	FlagDiffFile         = "diff-file"
+	FlagConcurrency      = "concurrency"
+	FlagConfig           = "config"
+	FlagReplaceCanonical = "replace-canonical"
+	FlagReport           = "report"
+	FlagSince            = "since"
+)
+
+// Default transformation rules.
+const (
+	defaultBaseURL        = "https://doc.traefik.io"
+	defaultMaxTitleLength = 65
+	defaultRobots         = "index, nofollow"
+	defaultOldRobots      = "noindex, nofollow"
+	defaultVersionPattern = `^(?P<root>.*)/(?P<version>v\d+\.\d+(?:\.\d+)?)/(?P<path>.*\.html)$`
 )
 
+// defaultProducts returns the built-in rules of the products.
+func defaultProducts() map[string]ProductConfig {
+	return map[string]ProductConfig{
+		"traefik": {
+			Rewrites: []Rewrite{
+				// middlewares/foo/index.html -> middlewares/http/foo/index.html
+				{Pattern: `^middlewares/([^/]+/[^/]+\.html)$`, Replacement: "middlewares/http/$1"},
+			},
+		},
+	}
+}
+
 // Config is the bot configuration.
 type Config struct {
 	Path    string
 	Product string
+	DryRun  bool
+
+	// Diff enables the output of the unified diff of the <head> sections.
+	Diff bool
+	// DiffFile is the path of the patch file, the diff is written to stdout if empty.
+	DiffFile string
+
+	// Concurrency is the number of files processed in parallel, GOMAXPROCS if zero.
+	Concurrency int
+
+	// ReplaceCanonical enables the replacement of the incorrect canonical links for all the products.
+	ReplaceCanonical bool
+
+	// Since is a git revision, only the files added or modified since this revision are transformed if not empty.
+	Since string
+
+	// ReportFile is the path of the JSON report of the run, no report is written if empty.
+	ReportFile string
+
+	// Products holds the transformation rules by product name.
+	Products map[string]ProductConfig
+}
+
+// fileConfig is the content of the configuration file.
+type fileConfig struct {
+	Products map[string]ProductConfig `yaml:"products"`
+}
+
+// ProductConfig holds the transformation rules of a product.
+type ProductConfig struct {
+	// BaseURL is the URL of the documentation website.
+	BaseURL string `yaml:"baseURL"`
+	// MaxTitleLength is the maximum length of a page title, in runes.
+	MaxTitleLength int `yaml:"maxTitleLength"`
+	// TitleEllipsis is appended to the truncated titles.
+	TitleEllipsis string `yaml:"titleEllipsis"`
+	// Robots is the content of the robots meta tag.
+	Robots string `yaml:"robots"`
+	// RobotsPolicy adapts the robots meta tag to the age of the versions.
+	RobotsPolicy RobotsPolicy `yaml:"robotsPolicy"`
+	// VersionPattern matches the versioned pages,
+	// it must capture the documentation root, the version, and the path relative to the version folder,
+	// with the named groups root, version, and path, or with the 3 first groups.
+	VersionPattern string `yaml:"versionPattern"`
+	// Rewrites are applied to the relative path of a page to find its canonical page.
+	Rewrites []Rewrite `yaml:"rewrites"`
+	// CanonicalFallback is the strategy used when no canonical page is found ("ancestor" or empty).
+	// With "ancestor", the nearest parent page that exists in the latest documentation is used.
+	CanonicalFallback string `yaml:"canonicalFallback"`
+	// Redirects is the path of a YAML/JSON file containing the redirect map of the mkdocs-redirects plugin (redirect_maps).
+	Redirects string `yaml:"redirects"`
+	// DetectRedirects enables the detection of the redirect stub pages (meta refresh) in the latest documentation.
+	DetectRedirects bool `yaml:"detectRedirects"`
+	// ReplaceCanonical enables the replacement of the existing canonical links
+	// pointing at another host or inside a versioned folder.
+	ReplaceCanonical bool `yaml:"replaceCanonical"`
+	// Descriptions enables the generation of the missing or duplicated meta descriptions.
+	Descriptions bool `yaml:"descriptions"`
+	// DescriptionLength is the maximum length of a generated description, in runes.
+	DescriptionLength int `yaml:"descriptionLength"`
+	// Social enables the management of the Open Graph and Twitter card meta tags.
+	Social bool `yaml:"social"`
+	// SiteName is the content of the og:site_name meta tag, the product name if empty.
+	SiteName string `yaml:"siteName"`
+	// TwitterCard is the content of the twitter:card meta tag.
+	TwitterCard string `yaml:"twitterCard"`
+	// Alternates enables the links to the same page in the other versions of the documentation.
+	Alternates bool `yaml:"alternates"`
+	// StructuredData enables the injection of the JSON-LD structured data (BreadcrumbList and TechArticle).
+	StructuredData bool `yaml:"structuredData"`
+}
+
+// RobotsPolicy defines the robots meta tag of each version, the other versions use the Robots value.
+type RobotsPolicy struct {
+	// Previous is the content of the robots meta tag of the newest stable version folder
+	// (the previous minor of the latest documentation).
+	Previous string `yaml:"previous"`
+	// IndexedMajors is the number of major versions kept indexed, counted from the newest stable version folder.
+	// The older versions use the Old value, zero disables the rule.
+	IndexedMajors int `yaml:"indexedMajors"`
+	// Old is the content of the robots meta tag of the versions older than IndexedMajors.
+	Old string `yaml:"old"`
+	// EndOfLife are the end-of-life versions, "noarchive" is added to their robots meta tag.
+	EndOfLife []string `yaml:"endOfLife"`
+	// SupportedMajors is the number of supported major versions, counted from the newest stable version folder.
+	// The older versions are end-of-life, zero disables the rule.
+	SupportedMajors int `yaml:"supportedMajors"`
+	// Versions overrides the content of the robots meta tag by version.
+	Versions map[string]string `yaml:"versions"`
+}
+
+// Rewrite is a rewrite rule of a canonical path.
+type Rewrite struct {
+	Pattern     string `yaml:"pattern"`
+	Replacement string `yaml:"replacement"`
 }
 
 // NewConfig creates a new Config.
-func NewConfig(cliCtx *cli.Context) Config {
-	return Config{
-		Path:    cliCtx.Path(FlagPath),
-		Product: cliCtx.String(FlagProduct),
+func NewConfig(cliCtx *cli.Context) (Config, error) {
+	cfg := Config{
+		Path:             cliCtx.Path(FlagPath),
+		Product:          cliCtx.String(FlagProduct),
+		DryRun:           cliCtx.Bool(FlagDryRun),
+		Diff:             cliCtx.Bool(FlagDiff) || cliCtx.Path(FlagDiffFile) != "",
+		DiffFile:         cliCtx.Path(FlagDiffFile),
+		Concurrency:      cliCtx.Int(FlagConcurrency),
+		ReplaceCanonical: cliCtx.Bool(FlagReplaceCanonical),
+		ReportFile:       cliCtx.Path(FlagReport),
+		Since:            cliCtx.String(FlagSince),
+	}
+
+	if filename := cliCtx.Path(FlagConfig); filename != "" {
+		products, err := loadConfigFile(filename)
+		if err != nil {
+			return Config{}, err
+		}
+
+		cfg.Products = products
+	}
+
+	return cfg, nil
+}
+
+// loadConfigFile loads the transformation rules from a YAML file.
+func loadConfigFile(filename string) (map[string]ProductConfig, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = file.Close() }()
+
+	decoder := yaml.NewDecoder(file)
+	decoder.KnownFields(true)
+
+	var fc fileConfig
+
+	err = decoder.Decode(&fc)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the configuration file %s: %w", filename, err)
+	}
+
+	for product, pCfg := range fc.Products {
+		err = validateTitleLength(product, pCfg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid configuration file %s: %w", filename, err)
+		}
+	}
+
+	return fc.Products, nil
+}
+
+// validateTitleLength checks that the maximum title length leaves room for the title suffix (| Product | vX.Y),
+// the ellipsis, and at least one rune of the title.
+func validateTitleLength(product string, pCfg ProductConfig) error {
+	if pCfg.MaxTitleLength <= 0 {
+		return nil
 	}
+
+	ellipsis := pCfg.TitleEllipsis
+	if ellipsis == "" {
+		ellipsis = defaultTitleEllipsis
+	}
+
+	minLength := utf8.RuneCountInString(fmt.Sprintf("| %s | v0.0", productTitle(product))) + utf8.RuneCountInString(ellipsis) + 2
+
+	if pCfg.MaxTitleLength < minLength {
+		return fmt.Errorf("%s: maxTitleLength must be at least %d: %d", product, minLength, pCfg.MaxTitleLength)
+	}
+
+	return nil
+}
+
+// productConfig returns the rules of a product, completed with the default values.
+func (c Config) productConfig(product string) ProductConfig {
+	defaults := defaultProducts()[product]
+
+	pCfg, ok := c.Products[product]
+	if !ok {
+		pCfg = defaults
+	} else {
+		pCfg.Rewrites = mergeRewrites(pCfg.Rewrites, defaults.Rewrites)
+	}
+
+	if pCfg.BaseURL == "" {
+		pCfg.BaseURL = defaultBaseURL
+	}
+
+	if pCfg.MaxTitleLength <= 0 {
+		pCfg.MaxTitleLength = defaultMaxTitleLength
+	}
+
+	if pCfg.TitleEllipsis == "" {
+		pCfg.TitleEllipsis = defaultTitleEllipsis
+	}
+
+	if pCfg.DescriptionLength <= 0 {
+		pCfg.DescriptionLength = defaultDescriptionLength
+	}
+
+	if pCfg.SiteName == "" {
+		pCfg.SiteName = productTitle(product)
+	}
+
+	if pCfg.TwitterCard == "" {
+		pCfg.TwitterCard = defaultTwitterCard
+	}
+
+	if pCfg.Robots == "" {
+		pCfg.Robots = defaultRobots
+	}
+
+	if pCfg.RobotsPolicy.Old == "" {
+		pCfg.RobotsPolicy.Old = defaultOldRobots
+	}
+
+	if pCfg.VersionPattern == "" {
+		pCfg.VersionPattern = defaultVersionPattern
+	}
+
+	if c.ReplaceCanonical {
+		pCfg.ReplaceCanonical = true
+	}
+
+	return pCfg
+}
+
+// mergeRewrites appends the built-in rewrites of a product after the configured rewrites, without duplicates.
+func mergeRewrites(rewrites, builtin []Rewrite) []Rewrite {
+	if len(builtin) == 0 {
+		return rewrites
+	}
+
+	merged := append([]Rewrite{}, rewrites...)
+
+	for _, b := range builtin {
+		found := false
+
+		for _, r := range rewrites {
+			if r.Pattern == b.Pattern {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			merged = append(merged, b)
+		}
+	}
+
+	return merged
 }
@@ -0,0 +1,147 @@
+package transform
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_loadConfigFile(t *testing.T) {
+	products, err := loadConfigFile("./fixtures/config.yml")
+	require.NoError(t, err)
+
+	expected := map[string]ProductConfig{
+		"traefik-enterprise": {
+			BaseURL:        "https://doc.example.com",
+			MaxTitleLength: 70,
+			Robots:         "noindex, nofollow",
+			VersionPattern: `^(.*)/(v\d+\.\d+)/(.*\.html)$`,
+			Rewrites: []Rewrite{
+				{Pattern: "^plugins/(.+)$", Replacement: "features/plugins/$1"},
+			},
+		},
+	}
+
+	assert.Equal(t, expected, products)
+}
+
+func Test_loadConfigFile_unknownField(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "config.yml")
+
+	err := os.WriteFile(filename, []byte("products:\n  test:\n    foo: bar\n"), 0o600)
+	require.NoError(t, err)
+
+	_, err = loadConfigFile(filename)
+	require.Error(t, err)
+}
+
+func Test_loadConfigFile_maxTitleLength(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "config.yml")
+
+	err := os.WriteFile(filename, []byte("products:\n  traefik:\n    maxTitleLength: 10\n"), 0o600)
+	require.NoError(t, err)
+
+	_, err = loadConfigFile(filename)
+	require.Error(t, err)
+}
+
+func TestConfig_productConfig(t *testing.T) {
+	cfg := Config{
+		Products: map[string]ProductConfig{
+			"test": {Robots: "noindex"},
+			"traefik": {
+				Robots:   "noindex",
+				Rewrites: []Rewrite{{Pattern: "^plugins/(.+)$", Replacement: "features/plugins/$1"}},
+			},
+		},
+	}
+
+	testCases := []struct {
+		desc     string
+		product  string
+		cfg      *Config
+		expected ProductConfig
+	}{
+		{
+			desc:    "from configuration",
+			product: "test",
+			expected: ProductConfig{
+				BaseURL:           defaultBaseURL,
+				MaxTitleLength:    defaultMaxTitleLength,
+				TitleEllipsis:     defaultTitleEllipsis,
+				DescriptionLength: defaultDescriptionLength,
+				SiteName:          "Test",
+				TwitterCard:       defaultTwitterCard,
+				Robots:            "noindex",
+				RobotsPolicy:      RobotsPolicy{Old: defaultOldRobots},
+				VersionPattern:    defaultVersionPattern,
+			},
+		},
+		{
+			desc:    "configuration merged with the built-in rewrites",
+			product: "traefik",
+			expected: ProductConfig{
+				BaseURL:           defaultBaseURL,
+				MaxTitleLength:    defaultMaxTitleLength,
+				TitleEllipsis:     defaultTitleEllipsis,
+				DescriptionLength: defaultDescriptionLength,
+				SiteName:          "Traefik",
+				TwitterCard:       defaultTwitterCard,
+				Robots:            "noindex",
+				RobotsPolicy:      RobotsPolicy{Old: defaultOldRobots},
+				VersionPattern:    defaultVersionPattern,
+				Rewrites: append([]Rewrite{{Pattern: "^plugins/(.+)$", Replacement: "features/plugins/$1"}},
+					defaultProducts()["traefik"].Rewrites...),
+			},
+		},
+		{
+			desc:    "built-in rules",
+			product: "traefik",
+			cfg:     &Config{},
+			expected: ProductConfig{
+				BaseURL:           defaultBaseURL,
+				MaxTitleLength:    defaultMaxTitleLength,
+				TitleEllipsis:     defaultTitleEllipsis,
+				DescriptionLength: defaultDescriptionLength,
+				SiteName:          "Traefik",
+				TwitterCard:       defaultTwitterCard,
+				Robots:            defaultRobots,
+				RobotsPolicy:      RobotsPolicy{Old: defaultOldRobots},
+				VersionPattern:    defaultVersionPattern,
+				Rewrites:          defaultProducts()["traefik"].Rewrites,
+			},
+		},
+		{
+			desc:    "unknown product",
+			product: "foo",
+			expected: ProductConfig{
+				BaseURL:           defaultBaseURL,
+				MaxTitleLength:    defaultMaxTitleLength,
+				TitleEllipsis:     defaultTitleEllipsis,
+				DescriptionLength: defaultDescriptionLength,
+				SiteName:          "Foo",
+				TwitterCard:       defaultTwitterCard,
+				Robots:            defaultRobots,
+				RobotsPolicy:      RobotsPolicy{Old: defaultOldRobots},
+				VersionPattern:    defaultVersionPattern,
+			},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			c := cfg
+			if test.cfg != nil {
+				c = *test.cfg
+			}
+
+			assert.Equal(t, test.expected, c.productConfig(test.product))
+		})
+	}
+}
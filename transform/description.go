@@ -0,0 +1,240 @@
+package transform
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	xhtml "golang.org/x/net/html"
+)
+
+// defaultDescriptionLength is the maximum length of a generated description.
+const defaultDescriptionLength = 160
+
+// Description change reasons.
+const (
+	reasonMissing   = "missing"
+	reasonDuplicate = "duplicate"
+	reasonMultiple  = "multiple meta tags"
+)
+
+// DescriptionTransform generates the meta description of the pages under a versioned folder,
+// when the description is missing or duplicated across the pages.
+type DescriptionTransform struct {
+	*versionedPages
+
+	product string
+	cfg     ProductConfig
+
+	// descriptions counts the pages using a description, filled during the scan.
+	descriptions map[string]int
+}
+
+// NewDescriptionTransform creates a new DescriptionTransform.
+func NewDescriptionTransform(product string, cfg ProductConfig) (*DescriptionTransform, error) {
+	pages, err := newVersionedPages(product, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return newDescriptionTransform(product, cfg, pages), nil
+}
+
+func newDescriptionTransform(product string, cfg ProductConfig, pages *versionedPages) *DescriptionTransform {
+	return &DescriptionTransform{versionedPages: pages, product: product, cfg: cfg, descriptions: make(map[string]int)}
+}
+
+// Scan counts the description of a page to detect the duplicates.
+func (t *DescriptionTransform) Scan(filename string) error {
+	description, err := readDescription(filename)
+	if err != nil {
+		return fmt.Errorf("%s: %w", filename, err)
+	}
+
+	if description != "" {
+		t.descriptions[description]++
+	}
+
+	return nil
+}
+
+// EndScan reports the duplicated descriptions.
+func (t *DescriptionTransform) EndScan() {
+	var duplicates []string
+	for description, count := range t.descriptions {
+		if count > 1 {
+			duplicates = append(duplicates, description)
+		}
+	}
+
+	sort.Strings(duplicates)
+
+	for _, description := range duplicates {
+		log.Printf("[description] %q is used by %d pages", description, t.descriptions[description])
+	}
+}
+
+// Plan computes the meta description of a page without writing it.
+func (t *DescriptionTransform) Plan(filename string, content []byte) (Result, error) {
+	m, err := t.page(filename)
+	if err != nil {
+		return Result{}, err
+	}
+
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(content))
+	if err != nil {
+		return Result{}, err
+	}
+
+	var (
+		changes  []Change
+		modified bool
+	)
+
+	head := doc.Find("head").First()
+	metas := head.Find(`meta[name="description"]`)
+
+	// Removes the extra meta descriptions.
+	if metas.Length() > 1 {
+		metas.Slice(1, metas.Length()).Each(func(_ int, s *goquery.Selection) {
+			changes = append(changes, Change{Kind: kindDescription, Action: actionDelete, From: s.AttrOr("content", ""), Reason: reasonMultiple})
+		})
+
+		metas.Slice(1, metas.Length()).Remove()
+		metas = metas.First()
+		modified = true
+	}
+
+	current := normalizeSpaces(metas.AttrOr("content", ""))
+
+	var reason string
+
+	switch {
+	case current == "":
+		reason = reasonMissing
+	case t.descriptions[current] > 1:
+		reason = reasonDuplicate
+	}
+
+	if reason != "" {
+		change, changed := t.setDescription(doc, head, metas, current, m.Version)
+		if changed {
+			change.Reason = reason
+			changes = append(changes, change)
+			modified = true
+		} else if reason == reasonMissing {
+			changes = append(changes, Change{Kind: kindDescription, Action: actionMissing})
+		}
+	}
+
+	if !modified {
+		return Result{Path: filename, Version: m.Version, Changes: changes, Content: content}, nil
+	}
+
+	newContent, err := renderDocument(doc)
+	if err != nil {
+		return Result{}, err
+	}
+
+	return Result{Path: filename, Version: m.Version, Changes: changes, Content: newContent}, nil
+}
+
+func (t *DescriptionTransform) setDescription(doc *goquery.Document, head, metas *goquery.Selection, current, version string) (Change, bool) {
+	text := firstParagraph(doc)
+	if text == "" {
+		return Change{}, false
+	}
+
+	suffix := fmt.Sprintf("(%s %s)", productTitle(t.product), version)
+	description := shortenText(text, suffix, t.cfg.DescriptionLength, t.cfg.TitleEllipsis)
+
+	if description == current {
+		return Change{}, false
+	}
+
+	if metas.Length() == 0 {
+		head.AppendHtml(fmt.Sprintf(`<meta name="description" content="%s" />`, html.EscapeString(description)))
+
+		return Change{Kind: kindDescription, Action: actionAdd, To: description}, true
+	}
+
+	metas.SetAttr("content", description)
+
+	return Change{Kind: kindDescription, Action: actionUpdate, From: current, To: description}, true
+}
+
+// firstParagraph returns the text of the first non-empty paragraph of the main content of a page.
+func firstParagraph(doc *goquery.Document) string {
+	var text string
+
+	doc.Find("article p, .md-content p").EachWithBreak(func(_ int, s *goquery.Selection) bool {
+		text = normalizeSpaces(s.Text())
+		return text == ""
+	})
+
+	return text
+}
+
+// readDescription reads the meta description of a page, only the <head> section is read.
+func readDescription(filename string) (string, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return "", err
+	}
+
+	defer func() { _ = file.Close() }()
+
+	z := xhtml.NewTokenizer(bufio.NewReader(file))
+
+	for {
+		switch z.Next() {
+		case xhtml.ErrorToken:
+			if errors.Is(z.Err(), io.EOF) {
+				return "", nil
+			}
+
+			return "", z.Err()
+
+		case xhtml.StartTagToken, xhtml.SelfClosingTagToken:
+			token := z.Token()
+
+			switch token.Data {
+			case "body":
+				return "", nil
+			case "meta":
+				if getAttr(token, "name") == "description" {
+					return normalizeSpaces(getAttr(token, "content")), nil
+				}
+			}
+
+		case xhtml.EndTagToken:
+			if z.Token().Data == "head" {
+				return "", nil
+			}
+
+		default:
+		}
+	}
+}
+
+func getAttr(token xhtml.Token, key string) string {
+	for _, attr := range token.Attr {
+		if attr.Key == key {
+			return attr.Val
+		}
+	}
+
+	return ""
+}
+
+func normalizeSpaces(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
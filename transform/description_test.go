@@ -0,0 +1,128 @@
+package transform
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDescriptionTransform_Plan(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		content  string
+		expected []Change
+		meta     string
+	}{
+		{
+			desc:     "missing description",
+			content:  `<html><head><title>Foo</title></head><body><article><p></p><p>The  first   paragraph.</p><p>Second.</p></article></body></html>`,
+			expected: []Change{{Kind: kindDescription, Action: actionAdd, To: "The first paragraph. (Test v1.0)", Reason: reasonMissing}},
+			meta:     "The first paragraph. (Test v1.0)",
+		},
+		{
+			desc:     "duplicated description",
+			content:  `<html><head><meta name="description" content="Duplicated"></head><body><div class="md-content"><p>Content.</p></div></body></html>`,
+			expected: []Change{{Kind: kindDescription, Action: actionUpdate, From: "Duplicated", To: "Content. (Test v1.0)", Reason: reasonDuplicate}},
+			meta:     "Content. (Test v1.0)",
+		},
+		{
+			desc:     "multiple descriptions",
+			content:  `<html><head><meta name="description" content="Unique"><meta name="description" content="Other"></head><body><article><p>Content.</p></article></body></html>`,
+			expected: []Change{{Kind: kindDescription, Action: actionDelete, From: "Other", Reason: reasonMultiple}},
+			meta:     "Unique",
+		},
+		{
+			desc:     "unique description",
+			content:  `<html><head><meta name="description" content="Unique"></head><body><article><p>Content.</p></article></body></html>`,
+			expected: nil,
+			meta:     "Unique",
+		},
+		{
+			desc:     "no paragraph",
+			content:  `<html><head></head><body><p>Outside of the main content.</p></body></html>`,
+			expected: []Change{{Kind: kindDescription, Action: actionMissing}},
+			meta:     "",
+		},
+		{
+			desc:     "truncated description",
+			content:  `<html><head></head><body><article><p>Traefik is an open-source Edge Router that makes publishing your services a fun and easy experience.</p></article></body></html>`,
+			expected: []Change{{Kind: kindDescription, Action: actionAdd, To: "Traefik is an open-source Edge... (Test v1.0)", Reason: reasonMissing}},
+			meta:     "Traefik is an open-source Edge... (Test v1.0)",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			transform, err := NewDescriptionTransform("test", ProductConfig{
+				VersionPattern:    defaultVersionPattern,
+				DescriptionLength: 45,
+				TitleEllipsis:     defaultTitleEllipsis,
+			})
+			require.NoError(t, err)
+
+			transform.descriptions["Duplicated"] = 2
+			transform.descriptions["Unique"] = 1
+
+			res, err := transform.Plan("/site/v1.0/foo/index.html", []byte(test.content))
+			require.NoError(t, err)
+
+			assert.Equal(t, "v1.0", res.Version)
+			assert.Equal(t, test.expected, res.Changes)
+
+			if !hasContentChange(test.expected) {
+				assert.Equal(t, test.content, string(res.Content))
+			}
+
+			file := filepath.Join(t.TempDir(), "index.html")
+			err = os.WriteFile(file, res.Content, 0o600)
+			require.NoError(t, err)
+
+			description, err := readDescription(file)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.meta, description)
+		})
+	}
+}
+
+func TestDescriptionTransform_Scan(t *testing.T) {
+	dir := t.TempDir()
+
+	pages := map[string]string{
+		"a.html": `<html><head><meta name="description" content="Same   description"></head><body></body></html>`,
+		"b.html": `<html><head><meta name="description" content="Same description"></head><body></body></html>`,
+		"c.html": `<html><head><meta name="description" content="Other"></head><body></body></html>`,
+		"d.html": `<html><head></head><body><meta name="description" content="In the body"></body></html>`,
+	}
+
+	transform, err := NewDescriptionTransform("test", ProductConfig{VersionPattern: defaultVersionPattern})
+	require.NoError(t, err)
+
+	for name, content := range pages {
+		file := filepath.Join(dir, name)
+
+		err = os.WriteFile(file, []byte(content), 0o600)
+		require.NoError(t, err)
+
+		err = transform.Scan(file)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, map[string]int{"Same description": 2, "Other": 1}, transform.descriptions)
+}
+
+func hasContentChange(changes []Change) bool {
+	for _, change := range changes {
+		if change.Action != actionMissing {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,81 @@
+package transform
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// writeDiff writes the unified diff of the <head> section between the file on the disk and the transformed content.
+// The file names of the diff are relative to root.
+func writeDiff(w io.Writer, root string, res Result) error {
+	if res.Delete || res.Content == nil {
+		return nil
+	}
+
+	original, err := os.ReadFile(res.Path)
+	if err != nil {
+		return err
+	}
+
+	filename, err := filepath.Rel(root, res.Path)
+	if err != nil {
+		return err
+	}
+
+	text, err := headDiff(filepath.ToSlash(filename), original, res.Content)
+	if err != nil {
+		return err
+	}
+
+	if text == "" {
+		return nil
+	}
+
+	_, err = io.WriteString(w, text)
+
+	return err
+}
+
+func headDiff(filename string, original, transformed []byte) (string, error) {
+	a, err := extractHead(original)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", filename, err)
+	}
+
+	b, err := extractHead(transformed)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", filename, err)
+	}
+
+	diff := difflib.UnifiedDiff{
+		A:        difflib.SplitLines(a),
+		B:        difflib.SplitLines(b),
+		FromFile: "a/" + filename,
+		ToFile:   "b/" + filename,
+		Context:  3,
+	}
+
+	return difflib.GetUnifiedDiffString(diff)
+}
+
+// extractHead returns the <head> section of an HTML document.
+// The document is parsed and rendered to avoid reporting formatting differences.
+func extractHead(content []byte) (string, error) {
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(content))
+	if err != nil {
+		return "", err
+	}
+
+	head, err := goquery.OuterHtml(doc.Find("head"))
+	if err != nil {
+		return "", err
+	}
+
+	return head + "\n", nil
+}
@@ -0,0 +1,42 @@
+package transform
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_writeDiff(t *testing.T) {
+	root := t.TempDir()
+
+	copyFile(t, "index.html", "", root)
+	file := copyFile(t, "index.html", "v1.0", root)
+
+	res, err := planFile(file, []fileTransform{testPageTransform(t, "test")})
+	require.NoError(t, err)
+
+	buf := &bytes.Buffer{}
+
+	err = writeDiff(buf, root, res)
+	require.NoError(t, err)
+
+	diff := buf.String()
+
+	assert.Contains(t, diff, "--- a/v1.0/index.html\n")
+	assert.Contains(t, diff, "\n-    <title>Traefik</title>\n")
+	assert.Contains(t, diff, "\n+    <title>Traefik | Test | v1.0</title>\n")
+	assert.Contains(t, diff, `<link rel="canonical" href="https://doc.traefik.io/test/"/>`)
+	assert.Contains(t, diff, `<meta name="robots" content="index, nofollow"/>`)
+	assert.NotContains(t, diff, `<meta name="author" content="traefik.io"/>`)
+}
+
+func Test_writeDiff_delete(t *testing.T) {
+	buf := &bytes.Buffer{}
+
+	err := writeDiff(buf, "", Result{Path: "v1.0/sitemap.xml", Delete: true})
+	require.NoError(t, err)
+
+	assert.Empty(t, buf.String())
+}
@@ -0,0 +1,60 @@
+package transform
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+)
+
+// changedFiles returns the files added or modified since a git revision, and the untracked files.
+// A renamed file is an added file. The paths are joined to the root.
+func changedFiles(root, since string) (map[string]struct{}, error) {
+	files := make(map[string]struct{})
+
+	err := gitFiles(root, files, "diff", "--name-only", "--relative", "--no-renames", "--diff-filter=AM", "-z", since, "--")
+	if err != nil {
+		return nil, fmt.Errorf("unable to list the files changed since %s: %w", since, err)
+	}
+
+	err = gitFiles(root, files, "ls-files", "--others", "--exclude-standard", "-z")
+	if err != nil {
+		return nil, fmt.Errorf("unable to list the untracked files: %w", err)
+	}
+
+	return files, nil
+}
+
+// gitFiles adds the files listed by a git command (NUL separated paths, relative to the root) to files.
+func gitFiles(root string, files map[string]struct{}, args ...string) error {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = root
+
+	output, err := cmd.Output()
+	if err != nil {
+		return err
+	}
+
+	for _, name := range bytes.Split(output, []byte{0}) {
+		if len(name) == 0 {
+			continue
+		}
+
+		files[filepath.Join(root, filepath.FromSlash(string(name)))] = struct{}{}
+	}
+
+	return nil
+}
+
+// filterJobs keeps the jobs of the changed files.
+func filterJobs(jobs []*job, changed map[string]struct{}) []*job {
+	var filtered []*job
+
+	for _, j := range jobs {
+		if _, ok := changed[j.path]; ok {
+			filtered = append(filtered, j)
+		}
+	}
+
+	return filtered
+}
@@ -0,0 +1,90 @@
+package transform
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun_since(t *testing.T) {
+	root := t.TempDir()
+
+	copyFile(t, "index.html", "", root)
+	copyFile(t, "foo/index.html", "", root)
+	unchanged := copyFile(t, "index.html", "v1.0", root)
+	copyFile(t, "foo/index.html", "v1.2", root)
+
+	gitCmd(t, root, "init", "-q")
+	gitCmd(t, root, "add", "-A")
+	gitCmd(t, root, "commit", "-q", "-m", "init")
+
+	modified := copyFile(t, "index.html", "v1.1", root)
+	gitCmd(t, root, "add", "-A")
+	gitCmd(t, root, "commit", "-q", "-m", "v1.1")
+
+	added := copyFile(t, "foo/index.html", "v1.0", root)
+	gitCmd(t, root, "add", "-A")
+
+	gitCmd(t, root, "mv", "v1.2/foo/index.html", "v1.2/index.html")
+	renamed := filepath.Join(root, "v1.2", "index.html")
+
+	untracked := copyFile(t, "index.html", "v1.3", root)
+
+	before, err := os.ReadFile(unchanged)
+	require.NoError(t, err)
+
+	report, err := Run(Config{Path: root, Product: "test", Since: "HEAD~1"})
+	require.NoError(t, err)
+
+	var paths []string
+	for _, file := range report.Files {
+		paths = append(paths, file.Path)
+	}
+
+	assert.ElementsMatch(t, []string{"v1.0/foo/index.html", "v1.1/index.html", "v1.2/index.html", "v1.3/index.html"}, paths)
+
+	after, err := os.ReadFile(unchanged)
+	require.NoError(t, err)
+
+	assert.Equal(t, before, after)
+
+	for _, file := range []string{modified, added, renamed, untracked} {
+		content, errR := os.ReadFile(file)
+		require.NoError(t, errR)
+
+		// the canonical pages are resolved against the full latest documentation.
+		assert.Contains(t, string(content), `<link rel="canonical"`)
+	}
+}
+
+func Test_changedFiles_invalidRevision(t *testing.T) {
+	root := t.TempDir()
+
+	gitCmd(t, root, "init", "-q")
+
+	_, err := changedFiles(root, "unknown")
+	require.Error(t, err)
+}
+
+func Test_filterJobs(t *testing.T) {
+	jobs := []*job{{path: filepath.Join("a", "index.html")}, {path: filepath.Join("b", "index.html")}}
+
+	filtered := filterJobs(jobs, map[string]struct{}{filepath.Join("b", "index.html"): {}})
+
+	require.Len(t, filtered, 1)
+	assert.Equal(t, filepath.Join("b", "index.html"), filtered[0].path)
+}
+
+func gitCmd(t *testing.T, dir string, args ...string) {
+	t.Helper()
+
+	cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+	cmd.Dir = dir
+
+	output, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(output))
+}
@@ -2,12 +2,9 @@ package transform
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
-	"log"
-	"net/url"
 	"os"
-	"path"
-	"path/filepath"
 	"regexp"
 	"strings"
 
@@ -16,67 +13,88 @@ import (
 	"golang.org/x/text/language"
 )
 
-const rootURL = "https://doc.traefik.io"
-
-const maxTitleLength = 65
-
 // PageTransform transforms HTML files under a versioned folder.
 type PageTransform struct {
-	product string
-	pattern *regexp.Regexp
+	*versionedPages
+
+	product   string
+	cfg       ProductConfig
+	rewrites  []rewriteRule
+	redirects *redirects
 }
 
 // NewPageTransform created a new PageTransform.
-func NewPageTransform(product string) *PageTransform {
-	return &PageTransform{
-		product: product,
-		pattern: regexp.MustCompile(`^(.*)/(v\d+\.\d+)/(.*\.html)$`),
+func NewPageTransform(product string, cfg ProductConfig) (*PageTransform, error) {
+	pages, err := newVersionedPages(product, cfg)
+	if err != nil {
+		return nil, err
 	}
+
+	return newPageTransform(product, cfg, pages)
 }
 
-// Match return true if the file is under a versioned folder.
-func (t PageTransform) Match(filename string) bool {
-	return t.pattern.MatchString(filename)
+func newPageTransform(product string, cfg ProductConfig, pages *versionedPages) (*PageTransform, error) {
+	var rewrites []rewriteRule
+	for _, rw := range cfg.Rewrites {
+		exp, errC := regexp.Compile(rw.Pattern)
+		if errC != nil {
+			return nil, fmt.Errorf("invalid rewrite pattern for %s: %w", product, errC)
+		}
+
+		rewrites = append(rewrites, rewriteRule{exp: exp, replacement: rw.Replacement})
+	}
+
+	switch cfg.CanonicalFallback {
+	case "", fallbackAncestor:
+	default:
+		return nil, fmt.Errorf("invalid canonical fallback for %s: %s", product, cfg.CanonicalFallback)
+	}
+
+	redirects, err := newRedirects(cfg.Redirects, cfg.DetectRedirects, strings.TrimSuffix(cfg.BaseURL, "/")+"/"+product)
+	if err != nil {
+		return nil, fmt.Errorf("invalid redirect map for %s: %w", product, err)
+	}
+
+	return &PageTransform{
+		versionedPages: pages,
+		product:        product,
+		cfg:            cfg,
+		rewrites:       rewrites,
+		redirects:      redirects,
+	}, nil
 }
 
-// Apply applies HTML transformations.
-func (t PageTransform) Apply(filename string) error {
-	versions := t.pattern.FindStringSubmatch(filename)
-	if len(versions) < 4 {
-		return fmt.Errorf("version not found: %s", filename)
+// Plan computes HTML transformations without writing them.
+func (t PageTransform) Plan(filename string, original []byte) (Result, error) {
+	m, err := t.page(filename)
+	if err != nil {
+		return Result{}, err
 	}
 
-	v := versions[2]
+	v := m.Version
 
-	doc, err := readDocument(filename)
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(original))
 	if err != nil {
-		return err
+		return Result{}, err
 	}
 
+	var changes []Change
+
 	doc.Find("head").Each(func(i int, s *goquery.Selection) {
-		expectedRelPath := versions[3]
-		expectedAbs := filepath.Join(versions[1], expectedRelPath)
-
-		if t.product == "traefik" {
-			// exception your middlewares (middlewares/foo/index.html -> (middlewares/http/foo/index.html)
-			midExp := regexp.MustCompile(`^middlewares/([^/]+/[^/]+.html)`)
-			if midExp.MatchString(expectedRelPath) {
-				expectedRelPath = "middlewares/http/" + midExp.FindStringSubmatch(expectedRelPath)[1]
-				expectedAbs = filepath.Join(versions[1], expectedRelPath)
+		// Add (or replace) link canonical URL
+		if expectedRelPath, strategy, found := t.resolveCanonical(m.Root, m.RelPath); found {
+			if change, changed := t.setCanonical(s, expectedRelPath); changed {
+				change.Reason = strategy
+				changes = append(changes, change)
 			}
+		} else if change, missing := t.missingCanonical(s, m.RelPath); missing {
+			changes = append(changes, change)
 		}
 
-		// Add link canonical URL
-		if _, err = os.Stat(expectedAbs); err == nil {
-			t.addCanonical(s, expectedRelPath)
-			log.Printf("[canonical] %s Adding canonical link", filename)
-		}
-
-		// Add meta no follow
-		meta := s.Find(`meta[name="robots"][content="index, nofollow"]`)
-		if meta != nil && len(meta.Nodes) == 0 {
-			s.AppendHtml(`<meta name="robots" content="index, nofollow" />`)
-			log.Printf("[robots] %s Adding meta robots", filename)
+		// Add (or update) meta robots
+		robots := t.cfg.RobotsPolicy.robotsFor(t.cfg.Robots, v, t.folders.set(m.Root))
+		if change, changed := setRobots(s, robots); changed {
+			changes = append(changes, change)
 		}
 
 		// Adds a Suffix in a format | product-name | version
@@ -84,49 +102,36 @@ func (t PageTransform) Apply(filename string) error {
 		if title != nil {
 			titleText := title.Text()
 
-			productNameTitleCase := cases.Title(language.English).String(strings.ReplaceAll(t.product, "-", " "))
+			productNameTitleCase := productTitle(t.product)
 			suffix := fmt.Sprintf("| %s | %s", productNameTitleCase, v)
 
 			if !strings.Contains(titleText, suffix) {
-				newTitle := fmt.Sprintf("%s %s", strings.ReplaceAll(titleText, fmt.Sprintf(` - %s`, productNameTitleCase), ""), suffix)
-				if len(newTitle) > maxTitleLength {
-					maxNewTitleLength := maxTitleLength - len(suffix)
-					newTitle = fmt.Sprintf("%s... %s", titleText[:maxNewTitleLength-4], suffix)
-				}
+				baseTitle := strings.ReplaceAll(titleText, fmt.Sprintf(` - %s`, productNameTitleCase), "")
+				newTitle := shortenText(baseTitle, suffix, t.cfg.MaxTitleLength, t.cfg.TitleEllipsis)
 
 				title.SetText(newTitle)
+				changes = append(changes, Change{Kind: kindTitle, Action: actionUpdate, From: titleText, To: newTitle})
 			}
 		}
 	})
 
-	return writeFile(filename, doc)
-}
-
-func (t PageTransform) addCanonical(s *goquery.Selection, fp string) {
-	link := s.Find(`link[rel="canonical"]`)
-	if link == nil || len(link.Nodes) != 0 {
-		return
-	}
-
-	r, err := url.Parse(rootURL)
+	content, err := renderDocument(doc)
 	if err != nil {
-		log.Printf("ERROR: unable to parse the root URL: %s", rootURL)
-		return
+		return Result{}, err
 	}
 
-	cano, err := r.Parse(path.Join(t.product, filepath.Dir(fp), "/"))
-	if err != nil {
-		log.Printf("ERROR: unable to create canonical path: %s %s %s", rootURL, t.product, fp)
-		return
-	}
+	return Result{Path: filename, Version: v, Changes: changes, Content: content}, nil
+}
 
-	s.AppendHtml(fmt.Sprintf(`<link rel="canonical" href=%q />`, strings.TrimSuffix(cano.String(), "/")+"/"))
+// productTitle returns the product name in title case (traefik-enterprise -> Traefik Enterprise).
+func productTitle(product string) string {
+	return cases.Title(language.English).String(strings.ReplaceAll(product, "-", " "))
 }
 
-func writeFile(filename string, doc *goquery.Document) error {
+func renderDocument(doc *goquery.Document) ([]byte, error) {
 	html, err := doc.Html()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	replacer := strings.NewReplacer(
@@ -135,7 +140,7 @@ func writeFile(filename string, doc *goquery.Document) error {
 	)
 	html = replacer.Replace(html)
 
-	return os.WriteFile(filename, []byte(html), os.ModeAppend)
+	return []byte(html), nil
 }
 
 func readDocument(filename string) (*goquery.Document, error) {
@@ -3,6 +3,7 @@ package transform
 import (
 	"bytes"
 	"io"
+	"log"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -14,7 +15,7 @@ import (
 )
 
 func TestPageTransform_Match(t *testing.T) {
-	transform := NewPageTransform("test")
+	transform := testPageTransform(t, "test")
 
 	testCases := []struct {
 		path   string
@@ -60,7 +61,7 @@ func TestPageTransform_Match(t *testing.T) {
 	}
 }
 
-func TestPageTransform_Apply(t *testing.T) {
+func TestPageTransform_Plan(t *testing.T) {
 	if runtime.GOOS == "windows" {
 		t.Skip("windows")
 	}
@@ -112,16 +113,34 @@ func TestPageTransform_Apply(t *testing.T) {
 
 			file := copyFile(t, test.src, "v1.0", root)
 
-			transform := NewPageTransform(test.product)
+			transform := testPageTransform(t, test.product)
 
-			err := transform.Apply(file)
+			res, err := planFile(file, []fileTransform{transform})
+			require.NoError(t, err)
+
+			err = commit(log.Default(), res)
 			require.NoError(t, err)
 
 			compareFile(t, filepath.Join("./fixtures/output/", test.src), file, test.update)
+
+			// The transformation is idempotent.
+			res, err = planFile(file, []fileTransform{transform})
+			require.NoError(t, err)
+
+			assert.False(t, res.Modified)
 		})
 	}
 }
 
+func testPageTransform(t *testing.T, product string) *PageTransform {
+	t.Helper()
+
+	transform, err := NewPageTransform(product, Config{}.productConfig(product))
+	require.NoError(t, err)
+
+	return transform
+}
+
 func copyFile(t *testing.T, src, v, root string) string {
 	t.Helper()
 
@@ -189,3 +208,69 @@ func compareFile(t *testing.T, src, dst string, update bool) {
 
 	assert.Empty(t, text)
 }
+
+func TestNewPageTransform_invalid(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		cfg      ProductConfig
+		expected string
+	}{
+		{
+			desc:     "invalid version pattern",
+			cfg:      ProductConfig{VersionPattern: `^(.*/(v\d+`},
+			expected: "invalid version pattern for test: error parsing regexp: missing closing ): `^(.*/(v\\d+`",
+		},
+		{
+			desc:     "missing groups",
+			cfg:      ProductConfig{VersionPattern: `^(.*)/(v\d+\.\d+)/.*\.html$`},
+			expected: "invalid version pattern for test: 3 groups expected: ^(.*)/(v\\d+\\.\\d+)/.*\\.html$",
+		},
+		{
+			desc:     "same group for the root and the path",
+			cfg:      ProductConfig{VersionPattern: `^(.*)/(?P<version>v\d+\.\d+)/(?P<root>.*\.html)$`},
+			expected: "invalid version pattern for test: 3 groups expected: ^(.*)/(?P<version>v\\d+\\.\\d+)/(?P<root>.*\\.html)$",
+		},
+		{
+			desc:     "invalid rewrite",
+			cfg:      ProductConfig{VersionPattern: defaultVersionPattern, Rewrites: []Rewrite{{Pattern: "(foo"}}},
+			expected: "invalid rewrite pattern for test: error parsing regexp: missing closing ): `(foo`",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := NewPageTransform("test", test.cfg)
+			assert.EqualError(t, err, test.expected)
+		})
+	}
+}
+
+func TestPageTransform_Plan_config(t *testing.T) {
+	root := t.TempDir()
+
+	copyFile(t, "foo/index.html", "", root)
+	file := copyFile(t, "foo/index.html", "v1.0", root)
+
+	transform, err := NewPageTransform("test", ProductConfig{
+		BaseURL:        "https://doc.example.com",
+		MaxTitleLength: 70,
+		Robots:         "noindex, nofollow",
+		VersionPattern: defaultVersionPattern,
+	})
+	require.NoError(t, err)
+
+	res, err := planFile(file, []fileTransform{transform})
+	require.NoError(t, err)
+
+	err = commit(log.Default(), res)
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(file)
+	require.NoError(t, err)
+
+	assert.Contains(t, string(content), `<link rel="canonical" href="https://doc.example.com/test/foo/"/>`)
+	assert.Contains(t, string(content), `<meta name="robots" content="noindex, nofollow"/>`)
+}
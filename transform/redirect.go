@@ -0,0 +1,231 @@
+package transform
+
+import (
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// maxRedirects is the maximum number of redirections followed to find a canonical page.
+const maxRedirects = 10
+
+// redirects follows the redirections of the latest documentation.
+type redirects struct {
+	// maps is the redirect map (source path -> target path), relative to the latest documentation root.
+	maps map[string]string
+	// detect enables the detection of the redirect stub pages.
+	detect bool
+	// siteURL is the URL of the latest documentation of the product.
+	siteURL string
+
+	mu    sync.Mutex
+	stubs map[string]stubPage
+}
+
+// stubPage is the result of the detection of a redirect stub page.
+type stubPage struct {
+	// target is the path of the target page, empty if the target is outside of the latest documentation.
+	target string
+	// isStub is true if the page is a redirect stub page.
+	isStub bool
+}
+
+// newRedirects creates a new redirects.
+// filename is an optional YAML/JSON file using the format of the mkdocs-redirects plugin (redirect_maps).
+func newRedirects(filename string, detect bool, siteURL string) (*redirects, error) {
+	r := &redirects{
+		detect:  detect,
+		siteURL: strings.TrimSuffix(siteURL, "/") + "/",
+		stubs:   make(map[string]stubPage),
+	}
+
+	if filename == "" {
+		return r, nil
+	}
+
+	maps, err := loadRedirectMap(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	r.maps = maps
+
+	return r, nil
+}
+
+// loadRedirectMap loads a redirect map using the format of the mkdocs-redirects plugin:
+//
+//	redirect_maps:
+//	  'old.md': 'new.md'
+//	  'old/index.md': 'new/page.md'
+func loadRedirectMap(filename string) (map[string]string, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = file.Close() }()
+
+	var content struct {
+		RedirectMaps map[string]string `yaml:"redirect_maps"`
+	}
+
+	err = yaml.NewDecoder(file).Decode(&content)
+	if err != nil {
+		return nil, err
+	}
+
+	maps := make(map[string]string)
+	for src, dst := range content.RedirectMaps {
+		if isAbsoluteURL(dst) {
+			// external redirections can't be canonical pages.
+			continue
+		}
+
+		maps[normalizeRedirectPath(src)] = normalizeRedirectPath(dst)
+	}
+
+	return maps, nil
+}
+
+// follow follows the redirections of a page.
+// It returns the final path, true if at least one redirection has been followed,
+// and false if the redirections can't be resolved (loop, too many redirections, redirect stub page to an external page),
+// a redirect stub page is never returned as the final path.
+func (r *redirects) follow(root, relPath string) (string, bool, bool) {
+	if r == nil {
+		return relPath, false, true
+	}
+
+	var followed bool
+
+	visited := map[string]struct{}{relPath: {}}
+
+	for i := 0; i <= maxRedirects; i++ {
+		target, ok := r.maps[relPath]
+		if !ok {
+			stub := r.stub(root, relPath)
+			if !stub.isStub {
+				return relPath, followed, true
+			}
+
+			if stub.target == "" {
+				return "", false, false
+			}
+
+			target = stub.target
+		}
+
+		if _, seen := visited[target]; seen || i == maxRedirects {
+			return "", false, false
+		}
+
+		visited[target] = struct{}{}
+		relPath = target
+		followed = true
+	}
+
+	return "", false, false
+}
+
+// stub detects a redirect stub page (meta refresh).
+func (r *redirects) stub(root, relPath string) stubPage {
+	if !r.detect {
+		return stubPage{}
+	}
+
+	abs := filepath.Join(root, relPath)
+
+	r.mu.Lock()
+	stub, ok := r.stubs[abs]
+	r.mu.Unlock()
+
+	if ok {
+		return stub
+	}
+
+	stub = r.readStub(abs, relPath)
+
+	r.mu.Lock()
+	r.stubs[abs] = stub
+	r.mu.Unlock()
+
+	return stub
+}
+
+func (r *redirects) readStub(abs, relPath string) stubPage {
+	doc, err := readDocument(abs)
+	if err != nil {
+		return stubPage{}
+	}
+
+	content, ok := doc.Find(`meta[http-equiv="refresh"]`).Attr("content")
+	if !ok {
+		return stubPage{}
+	}
+
+	_, location, found := strings.Cut(content, "url=")
+	if !found {
+		return stubPage{}
+	}
+
+	location = strings.Trim(strings.TrimSpace(location), `'"`)
+
+	if isAbsoluteURL(location) {
+		if !strings.HasPrefix(location, r.siteURL) {
+			// external redirections can't be canonical pages.
+			return stubPage{isStub: true}
+		}
+
+		return stubPage{target: normalizeRedirectPath(strings.TrimPrefix(location, r.siteURL)), isStub: true}
+	}
+
+	target := normalizeRedirectPath(path.Join(path.Dir(relPath), location) + suffixSlash(location))
+	if strings.HasPrefix(target, "../") {
+		return stubPage{isStub: true}
+	}
+
+	return stubPage{target: target, isStub: true}
+}
+
+// normalizeRedirectPath converts a redirect path (mkdocs source file or URL path) to an HTML file path.
+func normalizeRedirectPath(p string) string {
+	p, _, _ = strings.Cut(p, "#")
+	p = strings.TrimPrefix(p, "/")
+
+	switch {
+	case p == "" || strings.HasSuffix(p, "/"):
+		return path.Join(p, "index.html")
+	case strings.HasSuffix(p, ".html"):
+		return path.Clean(p)
+	case strings.HasSuffix(p, ".md"):
+		p = strings.TrimSuffix(p, ".md")
+
+		if base := path.Base(p); base == "index" || base == "README" {
+			return path.Join(path.Dir(p), "index.html")
+		}
+
+		return path.Join(p, "index.html")
+	default:
+		return path.Join(p, "index.html")
+	}
+}
+
+func suffixSlash(p string) string {
+	if strings.HasSuffix(p, "/") {
+		return "/"
+	}
+
+	return ""
+}
+
+func isAbsoluteURL(raw string) bool {
+	u, err := url.Parse(raw)
+
+	return err == nil && u.IsAbs()
+}
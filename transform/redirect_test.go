@@ -0,0 +1,197 @@
+package transform
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_loadRedirectMap(t *testing.T) {
+	maps, err := loadRedirectMap("./fixtures/redirects.yml")
+	require.NoError(t, err)
+
+	expected := map[string]string{
+		"old/index.html":         "new/index.html",
+		"routing/old/index.html": "routing/overview/index.html",
+		"chain/index.html":       "old/index.html",
+	}
+
+	assert.Equal(t, expected, maps)
+}
+
+func Test_normalizeRedirectPath(t *testing.T) {
+	testCases := []struct {
+		path     string
+		expected string
+	}{
+		{path: "", expected: "index.html"},
+		{path: "/", expected: "index.html"},
+		{path: "index.md", expected: "index.html"},
+		{path: "README.md", expected: "index.html"},
+		{path: "foo.md", expected: "foo/index.html"},
+		{path: "foo/index.md", expected: "foo/index.html"},
+		{path: "foo/bar.md#anchor", expected: "foo/bar/index.html"},
+		{path: "foo/", expected: "foo/index.html"},
+		{path: "/foo/bar/", expected: "foo/bar/index.html"},
+		{path: "foo/bar", expected: "foo/bar/index.html"},
+		{path: "foo/page.html", expected: "foo/page.html"},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.path, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, normalizeRedirectPath(test.path))
+		})
+	}
+}
+
+func Test_redirects_follow(t *testing.T) {
+	root := t.TempDir()
+
+	writeStub := func(relPath, location string) {
+		t.Helper()
+
+		content := `<!doctype html><html><head><title>Redirecting...</title>` +
+			`<meta http-equiv="refresh" content="0; url=` + location + `"></head></html>`
+
+		err := os.MkdirAll(filepath.Join(root, filepath.Dir(relPath)), 0o700)
+		require.NoError(t, err)
+
+		err = os.WriteFile(filepath.Join(root, relPath), []byte(content), 0o600)
+		require.NoError(t, err)
+	}
+
+	writeStub("stub/index.html", "../moved/")
+	writeStub("absolute/index.html", "https://doc.traefik.io/test/moved/")
+	writeStub("external/index.html", "https://example.com/")
+	writeStub("loop/index.html", "../loop/")
+	writeStub("outside/index.html", "../../other/")
+
+	for i := 0; i <= maxRedirects; i++ {
+		writeStub(fmt.Sprintf("hop%d/index.html", i), fmt.Sprintf("../hop%d/", i+1))
+	}
+
+	r, err := newRedirects("./fixtures/redirects.yml", true, "https://doc.traefik.io/test")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		desc     string
+		relPath  string
+		expected string
+		followed bool
+		notFound bool
+	}{
+		{
+			desc:     "no redirection",
+			relPath:  "foo/index.html",
+			expected: "foo/index.html",
+		},
+		{
+			desc:     "too many redirections",
+			relPath:  "hop0/index.html",
+			notFound: true,
+		},
+		{
+			desc:     "redirect map",
+			relPath:  "old/index.html",
+			expected: "new/index.html",
+			followed: true,
+		},
+		{
+			desc:     "redirect map chain",
+			relPath:  "chain/index.html",
+			expected: "new/index.html",
+			followed: true,
+		},
+		{
+			desc:     "relative stub",
+			relPath:  "stub/index.html",
+			expected: "moved/index.html",
+			followed: true,
+		},
+		{
+			desc:     "absolute stub",
+			relPath:  "absolute/index.html",
+			expected: "moved/index.html",
+			followed: true,
+		},
+		{
+			desc:     "external stub",
+			relPath:  "external/index.html",
+			notFound: true,
+		},
+		{
+			desc:     "outside stub",
+			relPath:  "outside/index.html",
+			notFound: true,
+		},
+		{
+			desc:     "stub loop",
+			relPath:  "loop/index.html",
+			notFound: true,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			target, followed, ok := r.follow(root, test.relPath)
+
+			assert.Equal(t, !test.notFound, ok)
+			assert.Equal(t, test.expected, target)
+			assert.Equal(t, test.followed, followed)
+		})
+	}
+}
+
+func TestPageTransform_resolveCanonical_redirect(t *testing.T) {
+	root := t.TempDir()
+
+	err := os.MkdirAll(filepath.Join(root, "new"), 0o700)
+	require.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(root, "new", "index.html"), nil, 0o600)
+	require.NoError(t, err)
+
+	transform, err := NewPageTransform("test", ProductConfig{
+		VersionPattern: defaultVersionPattern,
+		Redirects:      "./fixtures/redirects.yml",
+	})
+	require.NoError(t, err)
+
+	relPath, strategy, found := transform.resolveCanonical(root, "old/index.html")
+
+	assert.True(t, found)
+	assert.Equal(t, "new/index.html", relPath)
+	assert.Equal(t, strategyRedirect, strategy)
+}
+
+func TestPageTransform_resolveCanonical_unresolvedStub(t *testing.T) {
+	root := t.TempDir()
+
+	err := os.MkdirAll(filepath.Join(root, "external"), 0o700)
+	require.NoError(t, err)
+
+	content := `<html><head><meta http-equiv="refresh" content="0; url=https://example.com/"></head></html>`
+
+	err = os.WriteFile(filepath.Join(root, "external", "index.html"), []byte(content), 0o600)
+	require.NoError(t, err)
+
+	transform, err := NewPageTransform("test", ProductConfig{
+		VersionPattern:  defaultVersionPattern,
+		DetectRedirects: true,
+	})
+	require.NoError(t, err)
+
+	_, _, found := transform.resolveCanonical(root, "external/index.html")
+
+	assert.False(t, found)
+}
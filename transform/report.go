@@ -0,0 +1,254 @@
+package transform
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Change kinds.
+const (
+	kindCanonical      = "canonical"
+	kindRobots         = "robots"
+	kindTitle          = "title"
+	kindSitemap        = "sitemap"
+	kindDescription    = "description"
+	kindSocial         = "social"
+	kindAlternate      = "alternate"
+	kindStructuredData = "structured-data"
+)
+
+// Change actions.
+const (
+	actionAdd     = "add"
+	actionUpdate  = "update"
+	actionReplace = "replace"
+	actionDelete  = "delete"
+	actionMissing = "missing"
+)
+
+// Change describes a modification of a file.
+type Change struct {
+	Kind   string `json:"kind"`
+	Action string `json:"action"`
+	From   string `json:"from,omitempty"`
+	To     string `json:"to,omitempty"`
+	// Reason explains the change (e.g. the strategy used to find a canonical page).
+	Reason string `json:"reason,omitempty"`
+}
+
+func (c Change) String() string {
+	var s string
+
+	switch {
+	case c.From != "" && c.To != "":
+		s = fmt.Sprintf("%s %q -> %q", c.Action, c.From, c.To)
+	case c.To != "":
+		s = fmt.Sprintf("%s %q", c.Action, c.To)
+	case c.From != "":
+		s = fmt.Sprintf("%s %q", c.Action, c.From)
+	default:
+		s = c.Action
+	}
+
+	if c.Reason != "" {
+		s += fmt.Sprintf(" (%s)", c.Reason)
+	}
+
+	return s
+}
+
+// Result is the outcome of a transformation, before being written on the disk.
+type Result struct {
+	Path    string
+	Version string
+	Changes []Change
+	// Content is the new content of the file.
+	Content []byte
+	// Delete is true if the file must be removed.
+	Delete bool
+	// Modified is true if the transformation changes the file on the disk.
+	Modified bool
+}
+
+// canonicalSummary summarizes the canonical link changes.
+func canonicalSummary(results []Result) string {
+	var totals Totals
+	for _, res := range results {
+		totals.add(res)
+	}
+
+	return fmt.Sprintf("canonical links: %d added, %d replaced, %d missing",
+		totals.CanonicalAdded, totals.CanonicalReplaced, totals.CanonicalMissing)
+}
+
+// writePlan writes a human-readable report of the planned changes.
+func writePlan(w io.Writer, results []Result) error {
+	var nbChanges, nbDeleted int
+
+	for _, res := range results {
+		_, err := fmt.Fprintln(w, res.Path)
+		if err != nil {
+			return err
+		}
+
+		for _, change := range res.Changes {
+			_, err = fmt.Fprintf(w, "\t%-10s %s\n", change.Kind, change)
+			if err != nil {
+				return err
+			}
+		}
+
+		nbChanges += len(res.Changes)
+
+		if res.Delete {
+			nbDeleted++
+		}
+	}
+
+	_, err := fmt.Fprintf(w, "\n[dry-run] %d files, %d changes, %d deletions\n", len(results), nbChanges, nbDeleted)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "[dry-run] %s\n", canonicalSummary(results))
+
+	return err
+}
+
+// Report is the structured summary of a run.
+type Report struct {
+	Product  string            `json:"product"`
+	DryRun   bool              `json:"dryRun,omitempty"`
+	Totals   Totals            `json:"totals"`
+	Versions map[string]Totals `json:"versions,omitempty"`
+	Files    []FileReport      `json:"files,omitempty"`
+	Errors   []string          `json:"errors,omitempty"`
+}
+
+// FileReport holds the changes of a file.
+type FileReport struct {
+	Path    string   `json:"path"`
+	Version string   `json:"version,omitempty"`
+	Changes []Change `json:"changes,omitempty"`
+}
+
+// Totals counts the changes.
+type Totals struct {
+	Files             int `json:"files"`
+	CanonicalAdded    int `json:"canonicalAdded"`
+	CanonicalReplaced int `json:"canonicalReplaced"`
+	CanonicalMissing  int `json:"canonicalMissing"`
+	RobotsAdded       int `json:"robotsAdded"`
+	RobotsUpdated     int `json:"robotsUpdated"`
+	TitlesChanged     int `json:"titlesChanged"`
+	SitemapsDeleted   int `json:"sitemapsDeleted"`
+	Descriptions      int `json:"descriptions"`
+	SocialTags        int `json:"socialTags"`
+	AlternatesChanged int `json:"alternatesChanged"`
+	StructuredData    int `json:"structuredData"`
+}
+
+// counterKey identifies the counter of a change, an empty action counts all the actions of a kind.
+type counterKey struct {
+	kind   string
+	action string
+}
+
+// counters returns the counters by change kind and action.
+func (t *Totals) counters() map[counterKey]*int {
+	return map[counterKey]*int{
+		{kind: kindCanonical, action: actionAdd}:      &t.CanonicalAdded,
+		{kind: kindCanonical, action: actionReplace}:  &t.CanonicalReplaced,
+		{kind: kindCanonical, action: actionMissing}:  &t.CanonicalMissing,
+		{kind: kindRobots, action: actionAdd}:         &t.RobotsAdded,
+		{kind: kindRobots, action: actionUpdate}:      &t.RobotsUpdated,
+		{kind: kindTitle}:                             &t.TitlesChanged,
+		{kind: kindSitemap, action: actionDelete}:     &t.SitemapsDeleted,
+		{kind: kindDescription, action: actionAdd}:    &t.Descriptions,
+		{kind: kindDescription, action: actionUpdate}: &t.Descriptions,
+		{kind: kindSocial}:                            &t.SocialTags,
+		{kind: kindAlternate}:                         &t.AlternatesChanged,
+		{kind: kindStructuredData}:                    &t.StructuredData,
+	}
+}
+
+func (t *Totals) add(res Result) {
+	t.Files++
+
+	counters := t.counters()
+
+	for _, change := range res.Changes {
+		counter, ok := counters[counterKey{kind: change.Kind, action: change.Action}]
+		if !ok {
+			counter, ok = counters[counterKey{kind: change.Kind}]
+		}
+
+		if ok {
+			*counter++
+		}
+	}
+}
+
+// newReport creates the report of a run.
+func newReport(cfg Config, product string, results []Result, runErr error) Report {
+	report := Report{
+		Product:  product,
+		DryRun:   cfg.DryRun,
+		Versions: make(map[string]Totals),
+	}
+
+	for _, res := range results {
+		path := res.Path
+		if rel, err := filepath.Rel(cfg.Path, res.Path); err == nil {
+			path = filepath.ToSlash(rel)
+		}
+
+		version := res.Version
+		if version == "" {
+			// the version is the first folder of the documentation.
+			version, _, _ = strings.Cut(path, "/")
+		}
+
+		report.Files = append(report.Files, FileReport{Path: path, Version: version, Changes: res.Changes})
+
+		report.Totals.add(res)
+
+		totals := report.Versions[version]
+		totals.add(res)
+		report.Versions[version] = totals
+	}
+
+	// the errors of the files are joined.
+	var joined interface{ Unwrap() []error }
+
+	switch {
+	case errors.As(runErr, &joined):
+		for _, err := range joined.Unwrap() {
+			report.Errors = append(report.Errors, err.Error())
+		}
+	case runErr != nil:
+		report.Errors = append(report.Errors, runErr.Error())
+	}
+
+	return report
+}
+
+// writeReport writes the report as JSON.
+func writeReport(filename string, report Report) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = file.Close() }()
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(report)
+}
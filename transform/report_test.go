@@ -0,0 +1,205 @@
+package transform
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_writePlan(t *testing.T) {
+	results := []Result{
+		{
+			Path: "test/v1.0/index.html",
+			Changes: []Change{
+				{Kind: kindCanonical, Action: actionAdd, To: "https://doc.traefik.io/test/", Reason: strategySamePath},
+				{Kind: kindRobots, Action: actionAdd, To: "index, nofollow"},
+				{Kind: kindTitle, Action: actionUpdate, From: "Traefik", To: "Traefik | Test | v1.0"},
+			},
+		},
+		{
+			Path:    "test/v1.0/sitemap.xml",
+			Changes: []Change{{Kind: kindSitemap, Action: actionDelete}},
+			Delete:  true,
+		},
+	}
+
+	buf := &bytes.Buffer{}
+
+	err := writePlan(buf, results)
+	require.NoError(t, err)
+
+	expected := `test/v1.0/index.html
+	canonical  add "https://doc.traefik.io/test/" (same-path)
+	robots     add "index, nofollow"
+	title      update "Traefik" -> "Traefik | Test | v1.0"
+test/v1.0/sitemap.xml
+	sitemap    delete
+
+[dry-run] 2 files, 4 changes, 1 deletions
+[dry-run] canonical links: 1 added, 0 replaced, 0 missing
+`
+
+	assert.Equal(t, expected, buf.String())
+}
+
+func Test_newReport(t *testing.T) {
+	results := []Result{
+		{
+			Path:    "/doc/test/v1.0/index.html",
+			Version: "v1.0",
+			Changes: []Change{
+				{Kind: kindCanonical, Action: actionAdd, To: "https://doc.traefik.io/test/"},
+				{Kind: kindRobots, Action: actionAdd, To: "index, nofollow"},
+				{Kind: kindTitle, Action: actionUpdate, From: "Traefik", To: "Traefik | Test | v1.0"},
+			},
+		},
+		{
+			Path:    "/doc/test/v1.0/foo/index.html",
+			Version: "v1.0",
+			Changes: []Change{
+				{Kind: kindCanonical, Action: actionMissing, From: "foo/index.html"},
+			},
+		},
+		{
+			Path:    "/doc/test/v2.0/index.html",
+			Version: "v2.0",
+			Changes: []Change{
+				{Kind: kindCanonical, Action: actionReplace, From: "https://doc.traefik.io/test/v2.0/", To: "https://doc.traefik.io/test/"},
+			},
+		},
+		{
+			Path:    "/doc/test/v2.0/sitemap.xml",
+			Changes: []Change{{Kind: kindSitemap, Action: actionDelete}},
+			Delete:  true,
+		},
+	}
+
+	report := newReport(Config{Path: "/doc/test"}, "test", results, errors.New("boom"))
+
+	expected := Report{
+		Product: "test",
+		Totals: Totals{
+			Files:             4,
+			CanonicalAdded:    1,
+			CanonicalReplaced: 1,
+			CanonicalMissing:  1,
+			RobotsAdded:       1,
+			TitlesChanged:     1,
+			SitemapsDeleted:   1,
+		},
+		Versions: map[string]Totals{
+			"v1.0": {Files: 2, CanonicalAdded: 1, CanonicalMissing: 1, RobotsAdded: 1, TitlesChanged: 1},
+			"v2.0": {Files: 2, CanonicalReplaced: 1, SitemapsDeleted: 1},
+		},
+		Files: []FileReport{
+			{Path: "v1.0/index.html", Version: "v1.0", Changes: results[0].Changes},
+			{Path: "v1.0/foo/index.html", Version: "v1.0", Changes: results[1].Changes},
+			{Path: "v2.0/index.html", Version: "v2.0", Changes: results[2].Changes},
+			{Path: "v2.0/sitemap.xml", Version: "v2.0", Changes: results[3].Changes},
+		},
+		Errors: []string{"boom"},
+	}
+
+	assert.Equal(t, expected, report)
+}
+
+func TestTotals_add(t *testing.T) {
+	res := Result{
+		Changes: []Change{
+			{Kind: kindCanonical, Action: actionAdd},
+			{Kind: kindCanonical, Action: actionReplace},
+			{Kind: kindCanonical, Action: actionMissing},
+			{Kind: kindRobots, Action: actionAdd},
+			{Kind: kindRobots, Action: actionUpdate},
+			{Kind: kindTitle, Action: actionUpdate},
+			{Kind: kindSitemap, Action: actionDelete},
+			{Kind: kindDescription, Action: actionAdd},
+			{Kind: kindDescription, Action: actionUpdate},
+			{Kind: kindDescription, Action: actionDelete},
+			{Kind: kindDescription, Action: actionMissing},
+			{Kind: kindSocial, Action: actionAdd},
+			{Kind: kindSocial, Action: actionDelete},
+			{Kind: kindAlternate, Action: actionUpdate},
+			{Kind: kindStructuredData, Action: actionAdd},
+		},
+	}
+
+	var totals Totals
+	totals.add(res)
+
+	expected := Totals{
+		Files:             1,
+		CanonicalAdded:    1,
+		CanonicalReplaced: 1,
+		CanonicalMissing:  1,
+		RobotsAdded:       1,
+		RobotsUpdated:     1,
+		TitlesChanged:     1,
+		SitemapsDeleted:   1,
+		Descriptions:      2,
+		SocialTags:        2,
+		AlternatesChanged: 1,
+		StructuredData:    1,
+	}
+
+	assert.Equal(t, expected, totals)
+}
+
+func Test_newReport_errors(t *testing.T) {
+	report := newReport(Config{Path: "/doc/test"}, "test", nil, errors.Join(errors.New("boom"), errors.New("bang")))
+
+	assert.Equal(t, []string{"boom", "bang"}, report.Errors)
+}
+
+func TestRun_report(t *testing.T) {
+	root := t.TempDir()
+
+	copyFile(t, "index.html", "", root)
+	copyFile(t, "index.html", "v1.0", root)
+	copyFile(t, "sitemap.xml", "v1.0", root)
+
+	reportFile := filepath.Join(t.TempDir(), "report.json")
+
+	report, err := Run(Config{Path: root, Product: "test", ReportFile: reportFile})
+	require.NoError(t, err)
+
+	file, err := os.Open(reportFile)
+	require.NoError(t, err)
+
+	defer func() { _ = file.Close() }()
+
+	var written Report
+	err = json.NewDecoder(file).Decode(&written)
+	require.NoError(t, err)
+
+	assert.Equal(t, report, written)
+
+	expected := Totals{Files: 2, CanonicalAdded: 1, RobotsAdded: 1, TitlesChanged: 1, SitemapsDeleted: 1}
+	assert.Equal(t, expected, report.Totals)
+	assert.Equal(t, map[string]Totals{"v1.0": expected}, report.Versions)
+}
+
+func TestRun_report_prefixedFolders(t *testing.T) {
+	root := t.TempDir()
+
+	copyFile(t, "index.html", "", root)
+	copyFile(t, "index.html", "version-2.1", root)
+	copyFile(t, "sitemap.xml", "version-2.1", root)
+
+	products := map[string]ProductConfig{
+		"test": {VersionPattern: `^(?P<root>.*)/version-(?P<version>\d+\.\d+)/(?P<path>.*\.html)$`},
+	}
+
+	report, err := Run(Config{Path: root, Product: "test", Products: products, DryRun: true})
+	require.NoError(t, err)
+
+	// the sitemap and the pages of the folder are counted in the same version.
+	expected := Totals{Files: 2, CanonicalAdded: 1, RobotsAdded: 1, TitlesChanged: 1, SitemapsDeleted: 1}
+	assert.Equal(t, map[string]Totals{"2.1": expected}, report.Versions)
+}
@@ -0,0 +1,91 @@
+package transform
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/traefik/seo/versions"
+)
+
+// robotsNoArchive is added to the robots meta tag of the end-of-life versions.
+const robotsNoArchive = "noarchive"
+
+// robotsFor returns the content of the robots meta tag of a version.
+// set holds the version folders of the documentation.
+func (p RobotsPolicy) robotsFor(defaultRobots, version string, set versions.Set) string {
+	robots := defaultRobots
+
+	v, isVersion := versions.Parse(version)
+	// The newest version folder is the previous minor, the latest documentation being the unversioned root.
+	newest, hasNewest := set.Newest()
+
+	switch {
+	case p.Versions[version] != "":
+		robots = p.Versions[version]
+	case hasNewest && newest.Name == version && p.Previous != "":
+		robots = p.Previous
+	case isVersion && p.isOld(v, set):
+		robots = p.Old
+	}
+
+	if p.isEndOfLife(version, v, isVersion, set) && !hasRobotsDirective(robots, robotsNoArchive) {
+		robots += ", " + robotsNoArchive
+	}
+
+	return robots
+}
+
+// isOld returns true if a version is older than the indexed major versions.
+func (p RobotsPolicy) isOld(v versions.Version, set versions.Set) bool {
+	return p.IndexedMajors > 0 && set.MajorsBehind(v) >= p.IndexedMajors
+}
+
+// isEndOfLife returns true if a version is listed as end-of-life, or older than the supported major versions.
+// v is the parsed version, when isVersion is true.
+func (p RobotsPolicy) isEndOfLife(version string, v versions.Version, isVersion bool, set versions.Set) bool {
+	for _, eol := range p.EndOfLife {
+		if eol == version {
+			return true
+		}
+	}
+
+	return isVersion && set.IsEOL(v, p.SupportedMajors)
+}
+
+// setRobots adds the robots meta tag, or updates the content of the existing one.
+// It returns the change, or false if the page is unchanged.
+func setRobots(s *goquery.Selection, robots string) (Change, bool) {
+	metas := s.Find(`meta[name="robots"]`)
+
+	if metas.Length() == 0 {
+		s.AppendHtml(fmt.Sprintf(`<meta name="robots" content=%q />`, robots))
+
+		return Change{Kind: kindRobots, Action: actionAdd, To: robots}, true
+	}
+
+	current := metas.First().AttrOr("content", "")
+	if metas.Length() == 1 && current == robots {
+		return Change{}, false
+	}
+
+	change := Change{Kind: kindRobots, Action: actionUpdate, From: current, To: robots}
+	if metas.Length() > 1 {
+		change.Reason = reasonMultiple
+	}
+
+	metas.Slice(1, metas.Length()).Remove()
+	metas.First().SetAttr("content", robots)
+
+	return change, true
+}
+
+func hasRobotsDirective(robots, directive string) bool {
+	for _, d := range strings.Split(robots, ",") {
+		if strings.EqualFold(strings.TrimSpace(d), directive) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,177 @@
+package transform
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/traefik/seo/versions"
+)
+
+func TestRobotsPolicy_robotsFor(t *testing.T) {
+	policy := RobotsPolicy{
+		Previous:      "index, follow",
+		IndexedMajors: 2,
+		Old:           defaultOldRobots,
+		EndOfLife:     []string{"v1.7", "v2.0"},
+		Versions:      map[string]string{"v2.1": "noindex"},
+	}
+
+	folders := []string{"master", "v3.2-rc1", "v3.1", "v3.0", "v2.11", "v2.1", "v2.0", "v1.7"}
+
+	testCases := []struct {
+		desc     string
+		policy   RobotsPolicy
+		version  string
+		versions []string
+		expected string
+	}{
+		{
+			desc:     "previous minor",
+			policy:   policy,
+			version:  "v3.1",
+			versions: folders,
+			expected: "index, follow",
+		},
+		{
+			desc:     "indexed major",
+			policy:   policy,
+			version:  "v2.11",
+			versions: folders,
+			expected: defaultRobots,
+		},
+		{
+			desc:     "old major",
+			policy:   policy,
+			version:  "v1.7",
+			versions: folders,
+			expected: "noindex, nofollow, noarchive",
+		},
+		{
+			desc:     "end of life",
+			policy:   policy,
+			version:  "v2.0",
+			versions: folders,
+			expected: "index, nofollow, noarchive",
+		},
+		{
+			desc:     "development branch",
+			policy:   policy,
+			version:  "master",
+			versions: folders,
+			expected: defaultRobots,
+		},
+		{
+			desc:     "pre-release",
+			policy:   policy,
+			version:  "v3.2-rc1",
+			versions: folders,
+			expected: defaultRobots,
+		},
+		{
+			desc:     "unsupported major",
+			policy:   RobotsPolicy{SupportedMajors: 1},
+			version:  "v2.11",
+			versions: folders,
+			expected: "index, nofollow, noarchive",
+		},
+		{
+			desc:     "end of life folder that is not a version",
+			policy:   RobotsPolicy{EndOfLife: []string{"legacy"}},
+			version:  "legacy",
+			versions: folders,
+			expected: "index, nofollow, noarchive",
+		},
+		{
+			desc:     "version override",
+			policy:   policy,
+			version:  "v2.1",
+			versions: folders,
+			expected: "noindex",
+		},
+		{
+			desc:     "no policy",
+			policy:   RobotsPolicy{Old: defaultOldRobots},
+			version:  "v1.7",
+			versions: folders,
+			expected: defaultRobots,
+		},
+		{
+			desc:     "unknown versions",
+			policy:   policy,
+			version:  "v3.1",
+			expected: defaultRobots,
+		},
+		{
+			desc: "noarchive already set",
+			policy: RobotsPolicy{
+				Versions:  map[string]string{"v1.7": "noindex, NoArchive"},
+				EndOfLife: []string{"v1.7"},
+			},
+			version:  "v1.7",
+			versions: folders,
+			expected: "noindex, NoArchive",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, test.expected, test.policy.robotsFor(defaultRobots, test.version, versions.NewSet(test.versions)))
+		})
+	}
+}
+
+func Test_setRobots(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		head     string
+		expected Change
+		changed  bool
+	}{
+		{
+			desc:     "missing",
+			head:     `<title>Foo</title>`,
+			expected: Change{Kind: kindRobots, Action: actionAdd, To: "noindex, nofollow"},
+			changed:  true,
+		},
+		{
+			desc:     "different content",
+			head:     `<meta name="robots" content="index, nofollow">`,
+			expected: Change{Kind: kindRobots, Action: actionUpdate, From: "index, nofollow", To: "noindex, nofollow"},
+			changed:  true,
+		},
+		{
+			desc:     "multiple tags",
+			head:     `<meta name="robots" content="noindex, nofollow"><meta name="robots" content="index">`,
+			expected: Change{Kind: kindRobots, Action: actionUpdate, From: "noindex, nofollow", To: "noindex, nofollow", Reason: reasonMultiple},
+			changed:  true,
+		},
+		{
+			desc: "same content",
+			head: `<meta name="robots" content="noindex, nofollow">`,
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			doc, err := goquery.NewDocumentFromReader(strings.NewReader("<html><head>" + test.head + "</head><body></body></html>"))
+			require.NoError(t, err)
+
+			change, ok := setRobots(doc.Find("head"), "noindex, nofollow")
+			assert.Equal(t, test.changed, ok)
+			assert.Equal(t, test.expected, change)
+
+			metas := doc.Find(`meta[name="robots"]`)
+			require.Equal(t, 1, metas.Length())
+			assert.Equal(t, "noindex, nofollow", metas.AttrOr("content", ""))
+		})
+	}
+}
@@ -1,22 +1,33 @@
 package transform
 
 import (
-	"log"
-	"os"
+	"path/filepath"
 	"regexp"
 )
 
-// SitemapTransform transforms sitemap files.
+// SitemapTransform removes the sitemap files generated by mkdocs.
+// The sitemaps of the documentation are generated by the sitemap command, under other names (sitemap-1.xml).
 type SitemapTransform struct {
 	pattern *regexp.Regexp
+	pages   *versionedPages
 	product string
 }
 
 // NewSitemapTransform created a new SitemapTransform.
-func NewSitemapTransform(product string) *SitemapTransform {
+func NewSitemapTransform(product string, cfg ProductConfig) (*SitemapTransform, error) {
+	pages, err := newVersionedPages(product, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return newSitemapTransform(product, pages), nil
+}
+
+func newSitemapTransform(product string, pages *versionedPages) *SitemapTransform {
 	return &SitemapTransform{
 		product: product,
 		pattern: regexp.MustCompile(`/?([^/]+/)+sitemap\.xml(\.gz)?$`),
+		pages:   pages,
 	}
 }
 
@@ -25,9 +36,18 @@ func (t SitemapTransform) Match(path string) bool {
 	return t.pattern.MatchString(path)
 }
 
-// Apply removes a file.
-func (t SitemapTransform) Apply(path string) error {
-	// Remove sitemap files for versioned documentation.
-	log.Printf("[sitemap] %s deleted", path)
-	return os.Remove(path)
+// Plan plans the removal of a file.
+func (t SitemapTransform) Plan(path string, _ []byte) (Result, error) {
+	res := Result{
+		Path:    path,
+		Changes: []Change{{Kind: kindSitemap, Action: actionDelete}},
+		Delete:  true,
+	}
+
+	// the version is the one of the pages of the folder, if the sitemap is inside a version folder.
+	if m, ok := t.pages.pattern.match(filepath.Join(filepath.Dir(path), "index.html")); ok {
+		res.Version = m.Version
+	}
+
+	return res, nil
 }
@@ -1,14 +1,19 @@
 package transform
 
 import (
+	"log"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/traefik/seo/sitemap"
 )
 
 func TestSitemapTransform_Match(t *testing.T) {
-	transform := NewSitemapTransform("test")
+	transform, err := NewSitemapTransform("test", Config{}.productConfig("test"))
+	require.NoError(t, err)
 
 	testCases := []struct {
 		path   string
@@ -22,6 +27,14 @@ func TestSitemapTransform_Match(t *testing.T) {
 			path:   "foo/sitemap.xml.gz",
 			assert: assert.True,
 		},
+		{
+			path:   "foo/bar/sitemap.xml",
+			assert: assert.True,
+		},
+		{
+			path:   "foo/sitemap-1.xml",
+			assert: assert.False,
+		},
 		{
 			path:   "foo/v2.4/sitemap.xml",
 			assert: assert.True,
@@ -74,8 +87,9 @@ func TestSitemapTransform_Match(t *testing.T) {
 	}
 }
 
-func TestSitemapTransform_Apply(t *testing.T) {
-	transform := NewSitemapTransform("test")
+func TestSitemapTransform_Plan(t *testing.T) {
+	transform, err := NewSitemapTransform("test", Config{}.productConfig("test"))
+	require.NoError(t, err)
 
 	testCases := []struct {
 		desc string
@@ -102,10 +116,57 @@ func TestSitemapTransform_Apply(t *testing.T) {
 
 			file := copyFile(t, test.path, "v1.0", "")
 
-			err := transform.Apply(file)
+			res, err := planFile(file, []fileTransform{transform})
+			require.NoError(t, err)
+
+			err = commit(log.Default(), res)
 			require.NoError(t, err)
 
 			assert.NoFileExists(t, file)
 		})
 	}
 }
+
+func TestRun_sitemapGenerate(t *testing.T) {
+	root := t.TempDir()
+	product := filepath.Join(root, "test")
+
+	copyFile(t, "index.html", "", product)
+	copyFile(t, "sitemap.xml", "", product)
+	copyFile(t, "index.html", "v1.0", product)
+	versioned := copyFile(t, "sitemap.xml", "v1.0", product)
+
+	gitCmd(t, root, "init", "-q")
+	gitCmd(t, root, "add", "-A")
+	gitCmd(t, root, "commit", "-q", "-m", "init")
+
+	_, err := Run(Config{Path: product, Product: "test"})
+	require.NoError(t, err)
+
+	assert.NoFileExists(t, versioned)
+	assert.NoFileExists(t, filepath.Join(product, "sitemap.xml"))
+
+	err = sitemap.Generate(sitemap.Config{Root: root})
+	require.NoError(t, err)
+
+	gitCmd(t, root, "add", "-A")
+	gitCmd(t, root, "commit", "-q", "-m", "sitemap")
+
+	// a redeploy of the documentation brings back the sitemap generated by mkdocs.
+	copyFile(t, "sitemap.xml", "", product)
+	copyFile(t, "foo/index.html", "", product)
+	gitCmd(t, root, "add", "-A")
+	gitCmd(t, root, "commit", "-q", "-m", "redeploy")
+
+	// the product sitemap of the previous generation is read, not the mkdocs one.
+	err = sitemap.Generate(sitemap.Config{Root: root})
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(filepath.Join(product, "sitemap-1.xml"))
+	require.NoError(t, err)
+
+	assert.Contains(t, string(content), "<loc>https://doc.traefik.io/test/</loc>")
+	assert.Contains(t, string(content), "<loc>https://doc.traefik.io/test/foo/</loc>")
+	assert.NotContains(t, string(content), "v1.0")
+	assert.NotContains(t, string(content), "https://doc.traefik.io/traefik/")
+}
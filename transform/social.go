@@ -0,0 +1,140 @@
+package transform
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Open Graph defaults.
+const (
+	defaultTwitterCard = "summary"
+	openGraphType      = "article"
+)
+
+// SocialTransform manages the Open Graph and Twitter card meta tags of the pages under a versioned folder.
+// It must be applied after the PageTransform and the DescriptionTransform:
+// the tags are aligned to the canonical link, the title, and the description of the page.
+type SocialTransform struct {
+	*versionedPages
+
+	product string
+	cfg     ProductConfig
+}
+
+// socialTag is a meta tag identified by an attribute (property or name) and its value.
+type socialTag struct {
+	attr    string
+	key     string
+	content string
+	// removeEmpty removes the existing tag when the tag has no value.
+	removeEmpty bool
+}
+
+// NewSocialTransform creates a new SocialTransform.
+func NewSocialTransform(product string, cfg ProductConfig) (*SocialTransform, error) {
+	pages, err := newVersionedPages(product, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return newSocialTransform(product, cfg, pages), nil
+}
+
+func newSocialTransform(product string, cfg ProductConfig, pages *versionedPages) *SocialTransform {
+	return &SocialTransform{versionedPages: pages, product: product, cfg: cfg}
+}
+
+// Plan computes the Open Graph and Twitter card tags of a page without writing them.
+func (t *SocialTransform) Plan(filename string, content []byte) (Result, error) {
+	m, err := t.page(filename)
+	if err != nil {
+		return Result{}, err
+	}
+
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(content))
+	if err != nil {
+		return Result{}, err
+	}
+
+	head := doc.Find("head").First()
+
+	var changes []Change
+
+	for _, tag := range t.expectedTags(head) {
+		if change, changed := setSocialTag(head, tag); changed {
+			changes = append(changes, change)
+		}
+	}
+
+	if len(changes) == 0 {
+		return Result{Path: filename, Version: m.Version, Content: content}, nil
+	}
+
+	newContent, err := renderDocument(doc)
+	if err != nil {
+		return Result{}, err
+	}
+
+	return Result{Path: filename, Version: m.Version, Changes: changes, Content: newContent}, nil
+}
+
+// expectedTags returns the tags of a page, the tags without value are ignored,
+// except og:url which is removed when the page has no canonical link (a stale og:url is misleading).
+func (t *SocialTransform) expectedTags(head *goquery.Selection) []socialTag {
+	title := normalizeSpaces(head.Find("title").First().Text())
+	canonical := head.Find(`link[rel="canonical"]`).First().AttrOr("href", "")
+	description := normalizeSpaces(head.Find(`meta[name="description"]`).First().AttrOr("content", ""))
+
+	tags := []socialTag{
+		{attr: "property", key: "og:title", content: title},
+		{attr: "property", key: "og:url", content: canonical, removeEmpty: true},
+		{attr: "property", key: "og:description", content: description},
+		{attr: "property", key: "og:type", content: openGraphType},
+		{attr: "property", key: "og:site_name", content: t.cfg.SiteName},
+		{attr: "name", key: "twitter:card", content: t.cfg.TwitterCard},
+	}
+
+	var expected []socialTag
+	for _, tag := range tags {
+		if tag.content != "" || tag.removeEmpty {
+			expected = append(expected, tag)
+		}
+	}
+
+	return expected
+}
+
+// setSocialTag adds a tag, or rewrites its value, a tag without value is removed.
+func setSocialTag(head *goquery.Selection, tag socialTag) (Change, bool) {
+	metas := head.Find(fmt.Sprintf(`meta[%s=%q]`, tag.attr, tag.key))
+
+	if tag.content == "" {
+		if metas.Length() == 0 {
+			return Change{}, false
+		}
+
+		current := metas.First().AttrOr("content", "")
+		metas.Remove()
+
+		return Change{Kind: kindSocial, Action: actionDelete, From: current, Reason: tag.key}, true
+	}
+
+	if metas.Length() == 0 {
+		head.AppendHtml(fmt.Sprintf(`<meta %s=%q content="%s" />`, tag.attr, tag.key, html.EscapeString(tag.content)))
+
+		return Change{Kind: kindSocial, Action: actionAdd, To: tag.content, Reason: tag.key}, true
+	}
+
+	current := metas.First().AttrOr("content", "")
+	if metas.Length() == 1 && current == tag.content {
+		return Change{}, false
+	}
+
+	metas.Slice(1, metas.Length()).Remove()
+	metas.First().SetAttr("content", tag.content)
+
+	return Change{Kind: kindSocial, Action: actionUpdate, From: current, To: tag.content, Reason: tag.key}, true
+}
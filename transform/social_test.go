@@ -0,0 +1,139 @@
+package transform
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSocialTransform_Plan(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		content  string
+		expected map[string]string
+		removed  []string
+		changes  int
+	}{
+		{
+			desc:    "add tags",
+			content: `<html><head><title>Foo | Test | v1.0</title><link rel="canonical" href="https://doc.traefik.io/test/foo/"/><meta name="description" content="Foo bar."/></head><body></body></html>`,
+			expected: map[string]string{
+				`meta[property="og:title"]`:       "Foo | Test | v1.0",
+				`meta[property="og:url"]`:         "https://doc.traefik.io/test/foo/",
+				`meta[property="og:description"]`: "Foo bar.",
+				`meta[property="og:type"]`:        "article",
+				`meta[property="og:site_name"]`:   "Test",
+				`meta[name="twitter:card"]`:       "summary",
+			},
+			changes: 6,
+		},
+		{
+			desc:    "rewrite tags",
+			content: `<html><head><title>Foo | Test | v1.0</title><meta property="og:title" content="Foo - Test"/><meta property="og:title" content="Other"/><meta property="og:type" content="article"/><meta property="og:site_name" content="Test"/><meta name="twitter:card" content="summary"/></head><body></body></html>`,
+			expected: map[string]string{
+				`meta[property="og:title"]`:     "Foo | Test | v1.0",
+				`meta[property="og:type"]`:      "article",
+				`meta[property="og:site_name"]`: "Test",
+				`meta[name="twitter:card"]`:     "summary",
+			},
+			changes: 1,
+		},
+		{
+			desc:    "remove og:url without canonical",
+			content: `<html><head><title>Foo</title><meta property="og:url" content="https://doc.traefik.io/test/v1.0/foo/"/><meta property="og:title" content="Foo"/><meta property="og:type" content="article"/><meta property="og:site_name" content="Test"/><meta name="twitter:card" content="summary"/></head><body></body></html>`,
+			expected: map[string]string{
+				`meta[property="og:title"]`:     "Foo",
+				`meta[property="og:type"]`:      "article",
+				`meta[property="og:site_name"]`: "Test",
+				`meta[name="twitter:card"]`:     "summary",
+			},
+			removed: []string{`meta[property="og:url"]`},
+			changes: 1,
+		},
+		{
+			desc:    "up to date",
+			content: `<html><head><title>Foo</title><meta property="og:title" content="Foo"/><meta property="og:type" content="article"/><meta property="og:site_name" content="Test"/><meta name="twitter:card" content="summary"/></head><body></body></html>`,
+			expected: map[string]string{
+				`meta[property="og:title"]`:     "Foo",
+				`meta[property="og:type"]`:      "article",
+				`meta[property="og:site_name"]`: "Test",
+				`meta[name="twitter:card"]`:     "summary",
+			},
+		},
+	}
+
+	transform, err := NewSocialTransform("test", Config{}.productConfig("test"))
+	require.NoError(t, err)
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			res, err := transform.Plan("/site/v1.0/foo/index.html", []byte(test.content))
+			require.NoError(t, err)
+
+			assert.Equal(t, "v1.0", res.Version)
+			assert.Len(t, res.Changes, test.changes)
+
+			if test.changes == 0 {
+				assert.Equal(t, test.content, string(res.Content))
+			}
+
+			doc, err := goquery.NewDocumentFromReader(bytes.NewReader(res.Content))
+			require.NoError(t, err)
+
+			for selector, content := range test.expected {
+				metas := doc.Find(selector)
+				require.Equal(t, 1, metas.Length(), selector)
+
+				assert.Equal(t, content, metas.AttrOr("content", ""), selector)
+			}
+
+			for _, selector := range test.removed {
+				assert.Equal(t, 0, doc.Find(selector).Length(), selector)
+			}
+
+			// the tags are stable.
+			res, err = transform.Plan("/site/v1.0/foo/index.html", res.Content)
+			require.NoError(t, err)
+
+			assert.Empty(t, res.Changes)
+		})
+	}
+}
+
+func TestSocialTransform_afterPageTransform(t *testing.T) {
+	root := t.TempDir()
+
+	// Creates a fake latest version.
+	copyFile(t, "index.html", "", root)
+
+	file := copyFile(t, "index.html", "v1.0", root)
+
+	social, err := NewSocialTransform("test", Config{}.productConfig("test"))
+	require.NoError(t, err)
+
+	res, err := planFile(file, []fileTransform{testPageTransform(t, "test"), social})
+	require.NoError(t, err)
+
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(res.Content))
+	require.NoError(t, err)
+
+	assert.Equal(t, doc.Find(`link[rel="canonical"]`).AttrOr("href", ""), doc.Find(`meta[property="og:url"]`).AttrOr("content", "-"))
+	assert.Equal(t, doc.Find(`title`).Text(), doc.Find(`meta[property="og:title"]`).AttrOr("content", "-"))
+
+	// The chained transformations are idempotent.
+	err = commit(log.New(io.Discard, "", 0), res)
+	require.NoError(t, err)
+
+	res, err = planFile(file, []fileTransform{testPageTransform(t, "test"), social})
+	require.NoError(t, err)
+
+	assert.False(t, res.Modified)
+}
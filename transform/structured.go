@@ -0,0 +1,258 @@
+package transform
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// structuredDataID identifies the JSON-LD script managed by the StructuredDataTransform.
+const structuredDataID = "seo-structured-data"
+
+// StructuredDataTransform injects the JSON-LD structured data (BreadcrumbList and TechArticle)
+// of the pages under a versioned folder.
+// It must be applied after the PageTransform: the headline falls back to the rewritten title.
+type StructuredDataTransform struct {
+	*versionedPages
+
+	product string
+	cfg     ProductConfig
+}
+
+type structuredData struct {
+	Context string        `json:"@context"`
+	Graph   []interface{} `json:"@graph"`
+}
+
+type breadcrumbList struct {
+	Type     string     `json:"@type"`
+	Elements []listItem `json:"itemListElement"`
+}
+
+type listItem struct {
+	Type     string `json:"@type"`
+	Position int    `json:"position"`
+	Name     string `json:"name"`
+	Item     string `json:"item"`
+}
+
+type techArticle struct {
+	Type         string `json:"@type"`
+	Headline     string `json:"headline"`
+	URL          string `json:"url"`
+	Version      string `json:"version"`
+	About        string `json:"about"`
+	DateModified string `json:"dateModified,omitempty"`
+}
+
+// NewStructuredDataTransform creates a new StructuredDataTransform.
+func NewStructuredDataTransform(product string, cfg ProductConfig) (*StructuredDataTransform, error) {
+	pages, err := newVersionedPages(product, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return newStructuredDataTransform(product, cfg, pages), nil
+}
+
+func newStructuredDataTransform(product string, cfg ProductConfig, pages *versionedPages) *StructuredDataTransform {
+	return &StructuredDataTransform{versionedPages: pages, product: product, cfg: cfg}
+}
+
+// Plan computes the structured data of a page without writing it.
+func (t *StructuredDataTransform) Plan(filename string, content []byte) (Result, error) {
+	m, err := t.page(filename)
+	if err != nil {
+		return Result{}, err
+	}
+
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(content))
+	if err != nil {
+		return Result{}, err
+	}
+
+	head := doc.Find("head").First()
+	script := head.Find(fmt.Sprintf(`script[type="application/ld+json"][id=%q]`, structuredDataID))
+
+	data, err := t.structuredData(doc, script, filename, m)
+	if err != nil {
+		return Result{}, err
+	}
+
+	current := strings.TrimSpace(script.First().Text())
+	if script.Length() == 1 && current == data {
+		return Result{Path: filename, Version: m.Version, Content: content}, nil
+	}
+
+	change := Change{Kind: kindStructuredData, Action: actionAdd, Reason: "BreadcrumbList, TechArticle"}
+	if script.Length() > 0 {
+		change.Action = actionUpdate
+	}
+
+	script.Remove()
+	head.AppendHtml(fmt.Sprintf(`<script type="application/ld+json" id=%q>%s</script>`, structuredDataID, data))
+
+	newContent, err := renderDocument(doc)
+	if err != nil {
+		return Result{}, err
+	}
+
+	return Result{Path: filename, Version: m.Version, Changes: []Change{change}, Content: newContent}, nil
+}
+
+func (t *StructuredDataTransform) structuredData(doc *goquery.Document, script *goquery.Selection, filename string, m versionMatch) (string, error) {
+	base, err := url.Parse(t.cfg.BaseURL)
+	if err != nil {
+		return "", fmt.Errorf("unable to parse the root URL: %s: %w", t.cfg.BaseURL, err)
+	}
+
+	versionPath := path.Join(t.product, m.Folder)
+	version, relPath := m.Version, m.RelPath
+
+	// The permanent link added by mkdocs to the heading is not part of the headline.
+	h1 := doc.Find("h1").First().Clone()
+	h1.Find(".headerlink").Remove()
+
+	headline := normalizeSpaces(h1.Text())
+	if headline == "" {
+		// The title is rewritten by the PageTransform: the product and version suffix is not part of the headline.
+		suffix := fmt.Sprintf("| %s | %s", productTitle(t.product), version)
+		headline = strings.TrimSpace(strings.TrimSuffix(normalizeSpaces(doc.Find("title").First().Text()), suffix))
+	}
+
+	titles := navTitles(doc, relPath)
+
+	// The documentation root, the parent folders, then the page itself.
+	pages := []string{"index.html"}
+	parents := ancestors(relPath)
+	for i := len(parents) - 1; i >= 0; i-- {
+		if parents[i] != "index.html" {
+			pages = append(pages, parents[i])
+		}
+	}
+
+	if relPath != "index.html" {
+		pages = append(pages, relPath)
+	}
+
+	breadcrumb := breadcrumbList{Type: "BreadcrumbList"}
+
+	for i, p := range pages {
+		href, errU := breadcrumbURL(base, versionPath, p)
+		if errU != nil {
+			return "", fmt.Errorf("unable to create the breadcrumb URL: %s %s %s: %w", t.cfg.BaseURL, version, p, errU)
+		}
+
+		var name string
+
+		switch {
+		case i == 0:
+			name = fmt.Sprintf("%s %s", productTitle(t.product), version)
+		case p == relPath && headline != "":
+			name = headline
+		case titles[p] != "":
+			name = titles[p]
+		default:
+			name = segmentTitle(p)
+		}
+
+		breadcrumb.Elements = append(breadcrumb.Elements, listItem{Type: "ListItem", Position: i + 1, Name: name, Item: href})
+	}
+
+	article := techArticle{
+		Type:         "TechArticle",
+		Headline:     headline,
+		URL:          breadcrumb.Elements[len(breadcrumb.Elements)-1].Item,
+		Version:      version,
+		About:        productTitle(t.product),
+		DateModified: dateModified(doc, script, filename),
+	}
+
+	data, err := json.Marshal(structuredData{Context: "https://schema.org", Graph: []interface{}{breadcrumb, article}})
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+// breadcrumbURL returns the URL of a breadcrumb page: the folder URL for an index page, the page URL otherwise.
+func breadcrumbURL(base *url.URL, versionPath, relPath string) (string, error) {
+	if path.Base(relPath) == "index.html" {
+		return pageURL(base, versionPath, relPath)
+	}
+
+	u, err := base.Parse(path.Join(versionPath, relPath))
+	if err != nil {
+		return "", err
+	}
+
+	return u.String(), nil
+}
+
+// navTitles returns the titles of the mkdocs navigation links, by page path relative to the version folder.
+func navTitles(doc *goquery.Document, relPath string) map[string]string {
+	titles := make(map[string]string)
+
+	doc.Find("nav a.md-nav__link[href]").Each(func(_ int, s *goquery.Selection) {
+		href := s.AttrOr("href", "")
+		if href == "" || isAbsoluteURL(href) || strings.HasPrefix(href, "#") {
+			return
+		}
+
+		target := normalizeRedirectPath(path.Join(path.Dir(relPath), href) + suffixSlash(href))
+		if strings.HasPrefix(target, "../") {
+			return
+		}
+
+		if title := normalizeSpaces(s.Text()); title != "" {
+			if _, ok := titles[target]; !ok {
+				titles[target] = title
+			}
+		}
+	})
+
+	return titles
+}
+
+// segmentTitle returns a title built from the folder (or file) name of a page (routing/http-routers/index.html -> Http Routers).
+func segmentTitle(p string) string {
+	name := strings.TrimSuffix(path.Base(p), ".html")
+	if name == "index" {
+		name = path.Base(path.Dir(p))
+	}
+
+	return productTitle(strings.ReplaceAll(name, "_", "-"))
+}
+
+// dateModified returns the modification date of a page.
+// The sources are, in order, the article:modified_time meta tag, the current structured data, and the file modification time.
+func dateModified(doc *goquery.Document, script *goquery.Selection, filename string) string {
+	if date := doc.Find(`meta[property="article:modified_time"]`).AttrOr("content", ""); date != "" {
+		return date
+	}
+
+	var current structuredData
+	if err := json.Unmarshal([]byte(script.First().Text()), &current); err == nil {
+		for _, node := range current.Graph {
+			if obj, ok := node.(map[string]interface{}); ok && obj["@type"] == "TechArticle" {
+				if date, isString := obj["dateModified"].(string); isString && date != "" {
+					return date
+				}
+			}
+		}
+	}
+
+	info, err := os.Stat(filename)
+	if err != nil {
+		return ""
+	}
+
+	return info.ModTime().UTC().Format("2006-01-02")
+}
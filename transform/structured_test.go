@@ -0,0 +1,181 @@
+package transform
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStructuredDataTransform_Plan(t *testing.T) {
+	testCases := []struct {
+		desc       string
+		relPath    string
+		content    string
+		action     string
+		breadcrumb []listItem
+		article    techArticle
+	}{
+		{
+			desc:    "nested page with navigation titles",
+			relPath: "routing/http-routers/index.html",
+			content: `<html><head><title>Routers | Traefik Enterprise | v1.0</title><meta property="article:modified_time" content="2023-01-02"/></head>` +
+				`<body><nav class="md-nav"><a class="md-nav__link" href="../">Routing &amp; Load Balancing</a><a class="md-nav__link" href="https://example.com/">External</a></nav>` +
+				`<article><h1>HTTP Routers</h1></article></body></html>`,
+			action: actionAdd,
+			breadcrumb: []listItem{
+				{Type: "ListItem", Position: 1, Name: "Traefik Enterprise v1.0", Item: "https://doc.traefik.io/traefik-enterprise/v1.0/"},
+				{Type: "ListItem", Position: 2, Name: "Routing & Load Balancing", Item: "https://doc.traefik.io/traefik-enterprise/v1.0/routing/"},
+				{Type: "ListItem", Position: 3, Name: "HTTP Routers", Item: "https://doc.traefik.io/traefik-enterprise/v1.0/routing/http-routers/"},
+			},
+			article: techArticle{
+				Type:         "TechArticle",
+				Headline:     "HTTP Routers",
+				URL:          "https://doc.traefik.io/traefik-enterprise/v1.0/routing/http-routers/",
+				Version:      "v1.0",
+				About:        "Traefik Enterprise",
+				DateModified: "2023-01-02",
+			},
+		},
+		{
+			desc:    "folder names without navigation",
+			relPath: "getting_started/install.html",
+			content: `<html><head><title>Install | Traefik Enterprise | v1.0</title><meta property="article:modified_time" content="2023-01-02"/></head><body></body></html>`,
+			action:  actionAdd,
+			breadcrumb: []listItem{
+				{Type: "ListItem", Position: 1, Name: "Traefik Enterprise v1.0", Item: "https://doc.traefik.io/traefik-enterprise/v1.0/"},
+				{Type: "ListItem", Position: 2, Name: "Getting Started", Item: "https://doc.traefik.io/traefik-enterprise/v1.0/getting_started/"},
+				{Type: "ListItem", Position: 3, Name: "Install", Item: "https://doc.traefik.io/traefik-enterprise/v1.0/getting_started/install.html"},
+			},
+			article: techArticle{
+				Type:         "TechArticle",
+				Headline:     "Install",
+				URL:          "https://doc.traefik.io/traefik-enterprise/v1.0/getting_started/install.html",
+				Version:      "v1.0",
+				About:        "Traefik Enterprise",
+				DateModified: "2023-01-02",
+			},
+		},
+		{
+			desc:    "replace the structured data and keep the date",
+			relPath: "index.html",
+			content: `<html><head><script type="application/ld+json" id="seo-structured-data">{"@graph":[{"@type":"TechArticle","headline":"Old","dateModified":"2022-05-06"}]}</script></head><body><h1>Welcome</h1></body></html>`,
+			action:  actionUpdate,
+			breadcrumb: []listItem{
+				{Type: "ListItem", Position: 1, Name: "Traefik Enterprise v1.0", Item: "https://doc.traefik.io/traefik-enterprise/v1.0/"},
+			},
+			article: techArticle{
+				Type:         "TechArticle",
+				Headline:     "Welcome",
+				URL:          "https://doc.traefik.io/traefik-enterprise/v1.0/",
+				Version:      "v1.0",
+				About:        "Traefik Enterprise",
+				DateModified: "2022-05-06",
+			},
+		},
+	}
+
+	transform, err := NewStructuredDataTransform("traefik-enterprise", Config{}.productConfig("traefik-enterprise"))
+	require.NoError(t, err)
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			file := filepath.Join(t.TempDir(), "v1.0", test.relPath)
+
+			res, err := transform.Plan(file, []byte(test.content))
+			require.NoError(t, err)
+
+			require.Len(t, res.Changes, 1)
+			assert.Equal(t, test.action, res.Changes[0].Action)
+
+			doc, err := goquery.NewDocumentFromReader(bytes.NewReader(res.Content))
+			require.NoError(t, err)
+
+			scripts := doc.Find(`head script[type="application/ld+json"]`)
+			require.Equal(t, 1, scripts.Length())
+
+			var data struct {
+				Context string            `json:"@context"`
+				Graph   []json.RawMessage `json:"@graph"`
+			}
+
+			err = json.Unmarshal([]byte(scripts.Text()), &data)
+			require.NoError(t, err)
+
+			assert.Equal(t, "https://schema.org", data.Context)
+			require.Len(t, data.Graph, 2)
+
+			var breadcrumb breadcrumbList
+			err = json.Unmarshal(data.Graph[0], &breadcrumb)
+			require.NoError(t, err)
+
+			assert.Equal(t, "BreadcrumbList", breadcrumb.Type)
+			assert.Equal(t, test.breadcrumb, breadcrumb.Elements)
+
+			var article techArticle
+			err = json.Unmarshal(data.Graph[1], &article)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.article, article)
+
+			// The structured data is stable.
+			res, err = transform.Plan(file, res.Content)
+			require.NoError(t, err)
+
+			assert.Empty(t, res.Changes)
+		})
+	}
+}
+
+func TestStructuredDataTransform_Plan_headerlink(t *testing.T) {
+	file := copyFile(t, "foo/index.html", "v1.0", "")
+
+	content, err := os.ReadFile(file)
+	require.NoError(t, err)
+
+	transform, err := NewStructuredDataTransform("traefik-enterprise", Config{}.productConfig("traefik-enterprise"))
+	require.NoError(t, err)
+
+	res, err := transform.Plan(file, content)
+	require.NoError(t, err)
+
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(res.Content))
+	require.NoError(t, err)
+
+	var data struct {
+		Graph []json.RawMessage `json:"@graph"`
+	}
+
+	err = json.Unmarshal([]byte(doc.Find("#"+structuredDataID).Text()), &data)
+	require.NoError(t, err)
+	require.Len(t, data.Graph, 2)
+
+	var article techArticle
+	err = json.Unmarshal(data.Graph[1], &article)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Welcome", article.Headline)
+}
+
+func Test_dateModified_fileModTime(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "index.html")
+
+	err := os.WriteFile(file, nil, 0o600)
+	require.NoError(t, err)
+
+	info, err := os.Stat(file)
+	require.NoError(t, err)
+
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(nil))
+	require.NoError(t, err)
+
+	assert.Equal(t, info.ModTime().UTC().Format("2006-01-02"), dateModified(doc, doc.Find("script"), file))
+}
@@ -0,0 +1,53 @@
+package transform
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// defaultTitleEllipsis is appended to the truncated titles.
+const defaultTitleEllipsis = "..."
+
+// shortenText appends the suffix to a text (e.g. a title), and truncates the text to fit in maxLength runes.
+// The text is truncated at a word boundary when possible, and the ellipsis is appended to the truncated text.
+// The suffix is always preserved.
+func shortenText(text, suffix string, maxLength int, ellipsis string) string {
+	text = strings.TrimSpace(text)
+
+	newText := text + " " + suffix
+	if utf8.RuneCountInString(newText) <= maxLength {
+		return newText
+	}
+
+	available := maxLength - utf8.RuneCountInString(suffix) - utf8.RuneCountInString(ellipsis) - 1
+	if available <= 0 {
+		return newText
+	}
+
+	return truncateWords(text, available) + ellipsis + " " + suffix
+}
+
+// truncateWords truncates a text to maxLength runes, at the last word boundary if any.
+func truncateWords(text string, maxLength int) string {
+	runes := []rune(text)
+	if len(runes) <= maxLength {
+		return text
+	}
+
+	cut := maxLength
+
+	// Cuts at the last space if the truncation happens in the middle of a word.
+	if !unicode.IsSpace(runes[cut]) {
+		for i := cut - 1; i > 0; i-- {
+			if unicode.IsSpace(runes[i]) {
+				cut = i
+				break
+			}
+		}
+	}
+
+	return strings.TrimRightFunc(string(runes[:cut]), func(r rune) bool {
+		return unicode.IsSpace(r) || strings.ContainsRune("-–—,;:|", r)
+	})
+}
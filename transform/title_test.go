@@ -0,0 +1,140 @@
+package transform
+
+import (
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_shortenText(t *testing.T) {
+	testCases := []struct {
+		desc      string
+		title     string
+		suffix    string
+		maxLength int
+		ellipsis  string
+		expected  string
+	}{
+		{
+			desc:      "short title",
+			title:     "Overview",
+			suffix:    "| Traefik | v2.0",
+			maxLength: 65,
+			ellipsis:  "...",
+			expected:  "Overview | Traefik | v2.0",
+		},
+		{
+			desc:      "exact length",
+			title:     "Routing Configuration",
+			suffix:    "| Traefik | v2.0",
+			maxLength: 38,
+			ellipsis:  "...",
+			expected:  "Routing Configuration | Traefik | v2.0",
+		},
+		{
+			desc:      "word boundary",
+			title:     "Routing Configuration for the Kubernetes Ingress Controller",
+			suffix:    "| Traefik | v2.0",
+			maxLength: 50,
+			ellipsis:  "...",
+			expected:  "Routing Configuration for the... | Traefik | v2.0",
+		},
+		{
+			desc:      "cut on a space",
+			title:     "Routing Configuration for the Kubernetes Ingress Controller",
+			suffix:    "| Traefik | v2.0",
+			maxLength: 51,
+			ellipsis:  "...",
+			expected:  "Routing Configuration for the... | Traefik | v2.0",
+		},
+		{
+			desc:      "trailing punctuation",
+			title:     "Providers: Kubernetes, Docker, Consul, Nomad",
+			suffix:    "| Traefik | v2.0",
+			maxLength: 50,
+			ellipsis:  "...",
+			expected:  "Providers: Kubernetes, Docker... | Traefik | v2.0",
+		},
+		{
+			desc:      "custom ellipsis",
+			title:     "Routing Configuration for the Kubernetes Ingress Controller",
+			suffix:    "| Traefik | v2.0",
+			maxLength: 50,
+			ellipsis:  "…",
+			expected:  "Routing Configuration for the… | Traefik | v2.0",
+		},
+		{
+			desc:      "single long word",
+			title:     "Supercalifragilisticexpialidocious",
+			suffix:    "| Traefik | v2.0",
+			maxLength: 30,
+			ellipsis:  "...",
+			expected:  "Supercalif... | Traefik | v2.0",
+		},
+		{
+			desc:      "accented",
+			title:     "Démarrage rapide avec les éléments de configuration dynamique",
+			suffix:    "| Traefik | v2.0",
+			maxLength: 50,
+			ellipsis:  "...",
+			expected:  "Démarrage rapide avec les... | Traefik | v2.0",
+		},
+		{
+			desc:      "accented without truncation",
+			title:     "Démarrage rapide à l'échelle",
+			suffix:    "| Traefik | v2.0",
+			maxLength: 45,
+			ellipsis:  "...",
+			expected:  "Démarrage rapide à l'échelle | Traefik | v2.0",
+		},
+		{
+			desc:      "CJK",
+			title:     "路由配置和中间件的快速入门指南以及更多内容",
+			suffix:    "| Traefik | v2.0",
+			maxLength: 30,
+			ellipsis:  "…",
+			expected:  "路由配置和中间件的快速入… | Traefik | v2.0",
+		},
+		{
+			desc:      "CJK with spaces",
+			title:     "ルーティング 設定 ミドルウェア クイックスタート",
+			suffix:    "| Traefik | v2.0",
+			maxLength: 36,
+			ellipsis:  "...",
+			expected:  "ルーティング 設定 ミドルウェア... | Traefik | v2.0",
+		},
+		{
+			desc:      "CJK with spaces, word boundary",
+			title:     "ルーティング 設定 ミドルウェア クイックスタート",
+			suffix:    "| Traefik | v2.0",
+			maxLength: 35,
+			ellipsis:  "...",
+			expected:  "ルーティング 設定... | Traefik | v2.0",
+		},
+		{
+			desc:      "suffix too long",
+			title:     "Overview",
+			suffix:    "| Traefik | v2.0",
+			maxLength: 10,
+			ellipsis:  "...",
+			expected:  "Overview | Traefik | v2.0",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			title := shortenText(test.title, test.suffix, test.maxLength, test.ellipsis)
+
+			assert.Equal(t, test.expected, title)
+			assert.True(t, utf8.ValidString(title))
+
+			if test.expected != test.title+" "+test.suffix {
+				assert.LessOrEqual(t, utf8.RuneCountInString(title), test.maxLength)
+			}
+		})
+	}
+}
@@ -1,39 +1,404 @@
 package transform
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log"
 	"os"
 	"path/filepath"
+	"runtime"
+	"sync"
 )
 
 type fileTransform interface {
 	Match(path string) bool
-	Apply(path string) error
+	// Plan computes the transformation of the content of a file without writing it.
+	Plan(path string, content []byte) (Result, error)
+}
+
+// scanner scans all the matching files before the transformations.
+type scanner interface {
+	Match(path string) bool
+	Scan(path string) error
+	// EndScan is called once all the files have been scanned.
+	EndScan()
+}
+
+// job is the transformation of a file.
+type job struct {
+	path       string
+	transforms []fileTransform
+
+	res  Result
+	logs bytes.Buffer
+	diff bytes.Buffer
+	done chan struct{}
+
+	// err is the error of the processing of the file.
+	err error
+	// committed is true when the processing of the file is complete (changes written, or planned in dry-run mode).
+	committed bool
 }
 
 // Run applies transformations is needed.
-func Run(cfg Config) error {
+// It returns the report of the run, the report is also written to the report file if any.
+func Run(cfg Config) (Report, error) {
 	productName := getProductName(cfg)
 
-	transforms := []fileTransform{
-		NewPageTransform(productName),
-		NewSitemapTransform(productName),
+	results, err := run(cfg, productName)
+
+	report := newReport(cfg, productName, results, err)
+
+	if cfg.ReportFile != "" {
+		errW := writeReport(cfg.ReportFile, report)
+		if errW != nil && err == nil {
+			err = errW
+		}
+	}
+
+	return report, err
+}
+
+// run applies the transformations and returns the results of the processed files.
+func run(cfg Config, productName string) ([]Result, error) {
+	var diffOutput io.Writer
+	if cfg.Diff {
+		diffOutput = os.Stdout
+
+		if cfg.DiffFile != "" {
+			file, err := os.Create(cfg.DiffFile)
+			if err != nil {
+				return nil, err
+			}
+
+			defer func() { _ = file.Close() }()
+
+			diffOutput = file
+		}
+	}
+
+	results, err := process(cfg, productName, diffOutput)
+	if err != nil {
+		return results, err
+	}
+
+	if cfg.DryRun {
+		return results, writePlan(os.Stdout, results)
+	}
+
+	log.Printf("[canonical] %s", canonicalSummary(results))
+
+	return results, nil
+}
+
+// process processes the files of the documentation and returns the results of the processed files.
+func process(cfg Config, productName string, diffOutput io.Writer) ([]Result, error) {
+	transforms, scanners, err := newTransforms(cfg, productName)
+	if err != nil {
+		return nil, err
+	}
+
+	jobs, err := collectJobs(cfg.Path, transforms)
+	if err != nil {
+		return nil, err
+	}
+
+	err = scanJobs(jobs, scanners)
+	if err != nil {
+		return nil, err
+	}
+
+	// The scan uses all the files, only the changed files are transformed.
+	if cfg.Since != "" {
+		changed, errC := changedFiles(cfg.Path, cfg.Since)
+		if errC != nil {
+			return nil, errC
+		}
+
+		jobs = filterJobs(jobs, changed)
+	}
+
+	err = runJobs(cfg, jobs, diffOutput)
+
+	results := make([]Result, 0, len(jobs))
+	for _, j := range jobs {
+		if j.committed {
+			results = append(results, j.res)
+		}
+	}
+
+	return results, err
+}
+
+// newTransforms creates the transformations of a product, in the order they are applied,
+// and the scanners of the files.
+// The transformations share the version folders, discovered by the first scanner.
+func newTransforms(cfg Config, productName string) ([]fileTransform, []scanner, error) {
+	pCfg := cfg.productConfig(productName)
+
+	pages, err := newVersionedPages(productName, pCfg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	scanners := []scanner{versionScanner{pages: pages}}
+
+	pageTransform, err := newPageTransform(productName, pCfg, pages)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	transforms := []fileTransform{pageTransform}
+
+	if pCfg.Descriptions {
+		descriptionTransform := newDescriptionTransform(productName, pCfg, pages)
+
+		transforms = append(transforms, descriptionTransform)
+		scanners = append(scanners, descriptionTransform)
+	}
+
+	if pCfg.Social {
+		transforms = append(transforms, newSocialTransform(productName, pCfg, pages))
+	}
+
+	if pCfg.Alternates {
+		transforms = append(transforms, newAlternateTransform(productName, pCfg, pages))
 	}
 
-	return filepath.Walk(cfg.Path,
+	if pCfg.StructuredData {
+		transforms = append(transforms, newStructuredDataTransform(productName, pCfg, pages))
+	}
+
+	transforms = append(transforms, newSitemapTransform(productName, pages))
+
+	return transforms, scanners, nil
+}
+
+// collectJobs walks the documentation and creates a job for each file matching at least one transformation.
+func collectJobs(root string, transforms []fileTransform) ([]*job, error) {
+	var jobs []*job
+
+	err := filepath.Walk(root,
 		func(path string, info os.FileInfo, err error) error {
 			if err != nil {
 				return err
 			}
 
+			var matching []fileTransform
 			for _, transform := range transforms {
 				if transform.Match(path) {
-					return transform.Apply(path)
+					matching = append(matching, transform)
 				}
 			}
 
+			if len(matching) > 0 {
+				jobs = append(jobs, &job{path: path, transforms: matching, done: make(chan struct{})})
+			}
+
 			return nil
 		},
 	)
+	if err != nil {
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
+// scanJobs gives to the scanners a view of all the files before the transformations.
+func scanJobs(jobs []*job, scanners []scanner) error {
+	for _, j := range jobs {
+		for _, s := range scanners {
+			if !s.Match(j.path) {
+				continue
+			}
+
+			err := s.Scan(j.path)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, s := range scanners {
+		s.EndScan()
+	}
+
+	return nil
+}
+
+// runJobs processes the jobs with a bounded pool of workers.
+// The outputs of the jobs are flushed in the walk order.
+// The first error cancels all the workers.
+func runJobs(cfg Config, jobs []*job, diffOutput io.Writer) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	queue := make(chan *job)
+
+	go func() {
+		defer close(queue)
+
+		for _, j := range jobs {
+			select {
+			case <-ctx.Done():
+				return
+			case queue <- j:
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < getConcurrency(cfg); i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for j := range queue {
+				if ctx.Err() == nil {
+					j.err = processJob(cfg, j, diffOutput != nil)
+					if j.err != nil {
+						cancel()
+					}
+				}
+
+				close(j.done)
+			}
+		}()
+	}
+
+	var flushErr error
+
+	for _, j := range jobs {
+		select {
+		case <-ctx.Done():
+		case <-j.done:
+		}
+
+		if ctx.Err() != nil {
+			break
+		}
+
+		flushErr = flushJob(j, diffOutput)
+		if flushErr != nil {
+			cancel()
+			break
+		}
+	}
+
+	wg.Wait()
+
+	// All the errors are returned, in the order of the files.
+	var errs []error
+
+	for _, j := range jobs {
+		if j.err != nil {
+			errs = append(errs, j.err)
+		}
+	}
+
+	if flushErr != nil {
+		errs = append(errs, flushErr)
+	}
+
+	return errors.Join(errs...)
+}
+
+func processJob(cfg Config, j *job, withDiff bool) error {
+	res, err := planFile(j.path, j.transforms)
+	if err != nil {
+		return err
+	}
+
+	j.res = res
+
+	if withDiff {
+		err = writeDiff(&j.diff, cfg.Path, res)
+		if err != nil {
+			return err
+		}
+	}
+
+	// The content is not kept in memory after processing.
+	j.res.Content = nil
+
+	if !cfg.DryRun {
+		err = commit(log.New(&j.logs, log.Prefix(), log.Flags()), res)
+		if err != nil {
+			return err
+		}
+	}
+
+	j.committed = true
+
+	return nil
+}
+
+func flushJob(j *job, diffOutput io.Writer) error {
+	_, err := j.logs.WriteTo(log.Writer())
+	if err != nil {
+		return err
+	}
+
+	if diffOutput == nil {
+		return nil
+	}
+
+	_, err = j.diff.WriteTo(diffOutput)
+
+	return err
+}
+
+// planFile applies the transformations, in order, to the content of a file.
+func planFile(path string, transforms []fileTransform) (Result, error) {
+	original, err := os.ReadFile(path)
+	if err != nil {
+		return Result{}, err
+	}
+
+	res := Result{Path: path, Content: original}
+
+	for _, transform := range transforms {
+		r, errP := transform.Plan(path, res.Content)
+		if errP != nil {
+			return Result{}, errP
+		}
+
+		if res.Version == "" {
+			res.Version = r.Version
+		}
+
+		res.Changes = append(res.Changes, r.Changes...)
+		res.Content = r.Content
+
+		if r.Delete {
+			res.Delete = true
+			res.Content = nil
+
+			break
+		}
+	}
+
+	res.Modified = res.Delete || !bytes.Equal(original, res.Content)
+
+	return res, nil
+}
+
+// commit writes the result of a transformation on the disk.
+func commit(logger *log.Logger, res Result) error {
+	for _, change := range res.Changes {
+		logger.Printf("[%s] %s %s", change.Kind, res.Path, change)
+	}
+
+	if res.Delete {
+		return os.Remove(res.Path)
+	}
+
+	return os.WriteFile(res.Path, res.Content, os.ModeAppend)
 }
 
 func getProductName(cfg Config) string {
@@ -43,3 +408,11 @@ func getProductName(cfg Config) string {
 
 	return filepath.Base(cfg.Path)
 }
+
+func getConcurrency(cfg Config) int {
+	if cfg.Concurrency > 0 {
+		return cfg.Concurrency
+	}
+
+	return runtime.GOMAXPROCS(0)
+}
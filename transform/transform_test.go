@@ -1,9 +1,14 @@
 package transform
 
 import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func Test_getProductName(t *testing.T) {
@@ -48,3 +53,117 @@ func Test_getProductName(t *testing.T) {
 		})
 	}
 }
+
+func TestRun_dryRun(t *testing.T) {
+	root := t.TempDir()
+
+	copyFile(t, "index.html", "", root)
+	page := copyFile(t, "index.html", "v1.0", root)
+	sitemap := copyFile(t, "sitemap.xml", "v1.0", root)
+
+	before, err := os.ReadFile(page)
+	require.NoError(t, err)
+
+	_, err = Run(Config{Path: root, Product: "test", DryRun: true})
+	require.NoError(t, err)
+
+	after, err := os.ReadFile(page)
+	require.NoError(t, err)
+
+	assert.Equal(t, before, after)
+	assert.FileExists(t, sitemap)
+}
+
+func TestRun_concurrency(t *testing.T) {
+	root := t.TempDir()
+
+	copyFile(t, "index.html", "", root)
+
+	var files []string
+	for i := 0; i < 10; i++ {
+		files = append(files, copyFile(t, "index.html", fmt.Sprintf("v1.%d", i), root))
+	}
+
+	_, err := Run(Config{Path: root, Product: "test", Concurrency: 4})
+	require.NoError(t, err)
+
+	for _, file := range files {
+		content, errR := os.ReadFile(file)
+		require.NoError(t, errR)
+
+		v := filepath.Base(filepath.Dir(file))
+		assert.Contains(t, string(content), fmt.Sprintf("<title>Traefik | Test | %s</title>", v))
+	}
+}
+
+type failTransform struct {
+	failure string
+}
+
+func (t failTransform) Match(_ string) bool {
+	return true
+}
+
+func (t failTransform) Plan(path string, _ []byte) (Result, error) {
+	if filepath.Base(path) == t.failure {
+		return Result{}, errors.New("boom")
+	}
+
+	return Result{Path: path}, nil
+}
+
+func Test_runJobs_error(t *testing.T) {
+	root := t.TempDir()
+
+	var jobs []*job
+	for i := 0; i < 100; i++ {
+		path := filepath.Join(root, fmt.Sprintf("file%d", i))
+
+		err := os.WriteFile(path, nil, 0o600)
+		require.NoError(t, err)
+
+		jobs = append(jobs, &job{
+			path:       path,
+			transforms: []fileTransform{failTransform{failure: "file42"}},
+			done:       make(chan struct{}),
+		})
+	}
+
+	err := runJobs(Config{Concurrency: 8, DryRun: true}, jobs, nil)
+	require.EqualError(t, err, "boom")
+
+	assert.False(t, jobs[42].committed)
+	assert.True(t, jobs[0].committed)
+}
+
+// deleteTransform removes the file during the planning, so the commit of the deletion fails.
+type deleteTransform struct{}
+
+func (t deleteTransform) Match(_ string) bool {
+	return true
+}
+
+func (t deleteTransform) Plan(path string, _ []byte) (Result, error) {
+	err := os.Remove(path)
+	if err != nil {
+		return Result{}, err
+	}
+
+	return Result{Path: path, Delete: true}, nil
+}
+
+func Test_runJobs_notCommitted(t *testing.T) {
+	root := t.TempDir()
+
+	path := filepath.Join(root, "file")
+
+	err := os.WriteFile(path, nil, 0o600)
+	require.NoError(t, err)
+
+	j := &job{path: path, transforms: []fileTransform{deleteTransform{}}, done: make(chan struct{})}
+
+	err = runJobs(Config{Concurrency: 1}, []*job{j}, nil)
+	require.Error(t, err)
+
+	assert.False(t, j.committed)
+}
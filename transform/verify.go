@@ -0,0 +1,33 @@
+package transform
+
+import (
+	"fmt"
+	"log"
+	"strings"
+)
+
+// Verify runs the transformations in memory against an already processed documentation.
+// It returns an error listing the files that would be modified by Run.
+func Verify(cfg Config) error {
+	cfg.DryRun = true
+
+	results, err := process(cfg, getProductName(cfg), nil)
+	if err != nil {
+		return err
+	}
+
+	var modified []string
+	for _, res := range results {
+		if res.Modified {
+			modified = append(modified, res.Path)
+		}
+	}
+
+	if len(modified) > 0 {
+		return fmt.Errorf("%d files are not in their final SEO state:\n%s", len(modified), strings.Join(modified, "\n"))
+	}
+
+	log.Printf("[verify] %d files are in their final SEO state", len(results))
+
+	return nil
+}
@@ -0,0 +1,28 @@
+package transform
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerify(t *testing.T) {
+	root := t.TempDir()
+
+	copyFile(t, "index.html", "", root)
+	page := copyFile(t, "index2.html", "v1.0", root)
+	sitemap := copyFile(t, "sitemap.xml", "v1.0", root)
+
+	cfg := Config{Path: root, Product: "test"}
+
+	err := Verify(cfg)
+	require.EqualError(t, err, fmt.Sprintf("2 files are not in their final SEO state:\n%s\n%s", page, sitemap))
+
+	_, err = Run(cfg)
+	require.NoError(t, err)
+
+	err = Verify(cfg)
+	assert.NoError(t, err)
+}
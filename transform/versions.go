@@ -0,0 +1,191 @@
+package transform
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/traefik/seo/versions"
+)
+
+// Named groups of a version pattern.
+const (
+	groupRoot    = "root"
+	groupVersion = "version"
+	groupPath    = "path"
+)
+
+// versionPattern matches the pages under a version folder.
+// The groups are found by name (root, version, path), or by position (1, 2, 3).
+// Unlike versions.Folders, which matches the folder names listed by the robots and sitemap commands,
+// it matches the full path of a page: the transformation walks the files, not the folders,
+// and needs the documentation root and the path of the page relative to the version folder.
+type versionPattern struct {
+	exp *regexp.Regexp
+
+	root    int
+	version int
+	path    int
+}
+
+// versionMatch is a page under a version folder.
+type versionMatch struct {
+	// Root is the documentation root (the latest documentation).
+	Root string
+	// Folder is the name of the version folder (v2.10.3, version-2.1).
+	Folder string
+	// Version is the captured version (v2.10.3, 2.1).
+	Version string
+	// RelPath is the path of the page relative to the version folder.
+	RelPath string
+}
+
+func compileVersionPattern(product, pattern string) (*versionPattern, error) {
+	exp, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid version pattern for %s: %w", product, err)
+	}
+
+	p := &versionPattern{exp: exp, root: 1, version: 2, path: 3}
+
+	for name, index := range map[string]*int{groupRoot: &p.root, groupVersion: &p.version, groupPath: &p.path} {
+		if i := exp.SubexpIndex(name); i > 0 {
+			*index = i
+		}
+	}
+
+	if exp.NumSubexp() < 3 || p.root == p.version || p.root == p.path || p.version == p.path {
+		return nil, fmt.Errorf("invalid version pattern for %s: 3 groups expected: %s", product, pattern)
+	}
+
+	return p, nil
+}
+
+// MatchString returns true if the path is under a version folder.
+func (p *versionPattern) MatchString(s string) bool {
+	return p.exp.MatchString(s)
+}
+
+// match returns the version folder of a page.
+func (p *versionPattern) match(filename string) (versionMatch, bool) {
+	loc := p.exp.FindStringSubmatchIndex(filename)
+	if loc == nil || loc[2*p.version] < 0 || loc[2*p.root] < 0 || loc[2*p.path] < 0 {
+		return versionMatch{}, false
+	}
+
+	group := func(i int) string { return filename[loc[2*i]:loc[2*i+1]] }
+
+	// The version folder is the path segment containing the version.
+	start, end := loc[2*p.version], loc[2*p.version+1]
+	folderStart := strings.LastIndex(filename[:start], "/") + 1
+	folderEnd := len(filename)
+	if i := strings.Index(filename[end:], "/"); i >= 0 {
+		folderEnd = end + i
+	}
+
+	return versionMatch{
+		Root:    group(p.root),
+		Folder:  filename[folderStart:folderEnd],
+		Version: group(p.version),
+		RelPath: group(p.path),
+	}, true
+}
+
+// versionFolders holds the version folders (folder -> version) by documentation root.
+type versionFolders map[string]map[string]string
+
+func (v versionFolders) add(m versionMatch) {
+	if v[m.Root] == nil {
+		v[m.Root] = make(map[string]string)
+	}
+
+	v[m.Root][m.Folder] = m.Version
+}
+
+// sorted returns the version folders of a documentation root, from the newest to the oldest version.
+func (v versionFolders) sorted(root string) []versionMatch {
+	var folders []versionMatch
+	for folder, version := range v[root] {
+		folders = append(folders, versionMatch{Root: root, Folder: folder, Version: version})
+	}
+
+	sort.Slice(folders, func(i, j int) bool {
+		if c := versions.CompareNames(folders[i].Version, folders[j].Version); c != 0 {
+			return c > 0
+		}
+
+		return folders[i].Folder > folders[j].Folder
+	})
+
+	return folders
+}
+
+// set returns the versions of a documentation root.
+func (v versionFolders) set(root string) versions.Set {
+	var names []string
+	for _, version := range v[root] {
+		names = append(names, version)
+	}
+
+	return versions.NewSet(names)
+}
+
+// versionedPages matches the pages under a version folder, and holds the version folders of the documentation.
+// It is embedded by the transformations of a product, and shared between them:
+// the version folders are discovered once, by its versionScanner.
+type versionedPages struct {
+	pattern *versionPattern
+
+	// folders holds the version folders, filled during the scan.
+	folders versionFolders
+}
+
+func newVersionedPages(product string, cfg ProductConfig) (*versionedPages, error) {
+	pattern, err := compileVersionPattern(product, cfg.VersionPattern)
+	if err != nil {
+		return nil, err
+	}
+
+	return &versionedPages{pattern: pattern, folders: make(versionFolders)}, nil
+}
+
+// Match return true if the file is under a versioned folder.
+func (p *versionedPages) Match(filename string) bool {
+	return p.pattern.MatchString(filename)
+}
+
+// page returns the version folder of a page.
+func (p *versionedPages) page(filename string) (versionMatch, error) {
+	m, ok := p.pattern.match(filename)
+	if !ok {
+		return versionMatch{}, fmt.Errorf("version not found: %s", filename)
+	}
+
+	return m, nil
+}
+
+// versionScanner discovers the version folders of the versioned pages.
+type versionScanner struct {
+	pages *versionedPages
+}
+
+// Match return true if the file is under a versioned folder.
+func (s versionScanner) Match(filename string) bool {
+	return s.pages.Match(filename)
+}
+
+// Scan adds the version folder of a page.
+func (s versionScanner) Scan(filename string) error {
+	m, err := s.pages.page(filename)
+	if err != nil {
+		return err
+	}
+
+	s.pages.folders.add(m)
+
+	return nil
+}
+
+// EndScan does nothing, the version folders are used as-is.
+func (s versionScanner) EndScan() {}
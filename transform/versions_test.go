@@ -0,0 +1,131 @@
+package transform
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_versionPattern_match(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		pattern  string
+		filename string
+		expected versionMatch
+		found    bool
+	}{
+		{
+			desc:     "default pattern",
+			pattern:  defaultVersionPattern,
+			filename: "/site/v2.4/foo/index.html",
+			expected: versionMatch{Root: "/site", Folder: "v2.4", Version: "v2.4", RelPath: "foo/index.html"},
+			found:    true,
+		},
+		{
+			desc:     "patch version",
+			pattern:  defaultVersionPattern,
+			filename: "/site/v2.10.3/index.html",
+			expected: versionMatch{Root: "/site", Folder: "v2.10.3", Version: "v2.10.3", RelPath: "index.html"},
+			found:    true,
+		},
+		{
+			desc:     "prefixed version folder",
+			pattern:  `^(?P<root>.*)/version-(?P<version>\d+\.\d+)/(?P<path>.*\.html)$`,
+			filename: "/site/version-2.1/foo/bar.html",
+			expected: versionMatch{Root: "/site", Folder: "version-2.1", Version: "2.1", RelPath: "foo/bar.html"},
+			found:    true,
+		},
+		{
+			desc:     "positional groups",
+			pattern:  `^(.*)/(v\d+\.\d+)/(.*\.html)$`,
+			filename: "/site/v2.4/index.html",
+			expected: versionMatch{Root: "/site", Folder: "v2.4", Version: "v2.4", RelPath: "index.html"},
+			found:    true,
+		},
+		{
+			desc:     "named groups after an unnamed group",
+			pattern:  `^(?P<root>.*)/((?P<version>\d+\.\d+)-docs)/(?P<path>.*\.html)$`,
+			filename: "/site/2.4-docs/index.html",
+			expected: versionMatch{Root: "/site", Folder: "2.4-docs", Version: "2.4", RelPath: "index.html"},
+			found:    true,
+		},
+		{
+			desc:     "not versioned",
+			pattern:  defaultVersionPattern,
+			filename: "/site/foo/index.html",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			pattern, err := compileVersionPattern("test", test.pattern)
+			require.NoError(t, err)
+
+			m, ok := pattern.match(test.filename)
+			assert.Equal(t, test.found, ok)
+			assert.Equal(t, test.expected, m)
+		})
+	}
+}
+
+func TestPageTransform_prefixedVersion(t *testing.T) {
+	root := t.TempDir()
+
+	copyFile(t, "foo/index.html", "", root)
+
+	file := copyFile(t, "foo/index.html", "version-2.1", root)
+
+	transform, err := NewPageTransform("test", Config{
+		Products: map[string]ProductConfig{
+			"test": {VersionPattern: `^(?P<root>.*)/version-(?P<version>\d+\.\d+)/(?P<path>.*\.html)$`},
+		},
+	}.productConfig("test"))
+	require.NoError(t, err)
+
+	require.True(t, transform.Match(file))
+
+	res, err := planFile(file, []fileTransform{transform})
+	require.NoError(t, err)
+
+	assert.Equal(t, "2.1", res.Version)
+	assert.Contains(t, res.Changes, Change{Kind: kindCanonical, Action: actionAdd, To: "https://doc.traefik.io/test/foo/", Reason: strategySamePath})
+	assert.Contains(t, string(res.Content), "| Test | 2.1</title>")
+}
+
+func Test_newTransforms_sharedVersionScan(t *testing.T) {
+	root := t.TempDir()
+
+	cfg := Config{Products: map[string]ProductConfig{"test": {Alternates: true}}}
+
+	transforms, scanners, err := newTransforms(cfg, "test")
+	require.NoError(t, err)
+	require.Len(t, transforms, 3)
+	require.Len(t, scanners, 1)
+
+	for _, p := range []string{"v1.0/foo/index.html", "v2.0/foo/index.html"} {
+		file := filepath.Join(root, p)
+
+		require.True(t, scanners[0].Match(file))
+		require.NoError(t, scanners[0].Scan(file))
+	}
+
+	scanners[0].EndScan()
+
+	page, ok := transforms[0].(*PageTransform)
+	require.True(t, ok)
+
+	alternate, ok := transforms[1].(*AlternateTransform)
+	require.True(t, ok)
+
+	expected := []versionMatch{
+		{Root: root, Folder: "v2.0", Version: "v2.0"},
+		{Root: root, Folder: "v1.0", Version: "v1.0"},
+	}
+	assert.Equal(t, expected, page.folders.sorted(root))
+	assert.Same(t, page.versionedPages, alternate.versionedPages)
+}
@@ -0,0 +1,102 @@
+package versions
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+)
+
+// DefaultFolderPattern matches the default version folders (v2.4, v2.10.3, v3.0-rc1) and the development branch folder.
+const DefaultFolderPattern = `^(?:v\d+\.\d+(?:\.\d+)?(?:-[0-9A-Za-z.]+)?|` + Development + `)$`
+
+// groupVersion is the named group capturing the version in a folder pattern.
+const groupVersion = "version"
+
+// Folders detects the version folders by name.
+// The transform command matches the full path of the pages instead, see its version pattern.
+type Folders struct {
+	exp *regexp.Regexp
+	// version is the index of the group capturing the version, 0 for the whole name.
+	version int
+}
+
+// NewFolders creates a Folders from a pattern matching the names of the version folders (version-2.1).
+// The version is captured by the group named version, or by the first group, or is the whole name.
+// An empty pattern uses DefaultFolderPattern.
+func NewFolders(pattern string) (*Folders, error) {
+	if pattern == "" {
+		pattern = DefaultFolderPattern
+	}
+
+	exp, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid version folder pattern: %w", err)
+	}
+
+	f := &Folders{exp: exp}
+
+	switch {
+	case exp.SubexpIndex(groupVersion) > 0:
+		f.version = exp.SubexpIndex(groupVersion)
+	case exp.NumSubexp() > 0:
+		f.version = 1
+	}
+
+	return f, nil
+}
+
+// Parse parses the name of a version folder, the name of the version is the folder name.
+func (f *Folders) Parse(name string) (Version, bool) {
+	parts := f.exp.FindStringSubmatch(name)
+	if parts == nil || parts[f.version] == "" {
+		return Version{}, false
+	}
+
+	v, ok := Parse(parts[f.version])
+	if !ok {
+		return Version{}, false
+	}
+
+	v.Name = name
+
+	return v, true
+}
+
+// IsVersion returns true if the name is a version folder.
+func (f *Folders) IsVersion(name string) bool {
+	_, ok := f.Parse(name)
+	return ok
+}
+
+// NewSet creates a Set from the names of the version folders, the other names are ignored.
+func (f *Folders) NewSet(names []string) Set {
+	var set Set
+
+	for _, name := range names {
+		if v, ok := f.Parse(name); ok {
+			set = append(set, v)
+		}
+	}
+
+	set.sort()
+
+	return set
+}
+
+// List lists the version folders of the documentation of a product.
+func (f *Folders) List(dir string) (Set, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+
+	return f.NewSet(names), nil
+}
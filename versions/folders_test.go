@@ -0,0 +1,102 @@
+package versions
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFolders_Parse(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		pattern  string
+		name     string
+		expected Version
+		ok       bool
+	}{
+		{
+			desc:     "default",
+			name:     "v2.10.3",
+			expected: Version{Name: "v2.10.3", Major: 2, Minor: 10, Patch: 3},
+			ok:       true,
+		},
+		{
+			desc:     "default development",
+			name:     "master",
+			expected: Version{Name: "master", Dev: true},
+			ok:       true,
+		},
+		{
+			desc: "default without prefix",
+			name: "2.1",
+		},
+		{
+			desc:     "named group",
+			pattern:  `^version-(?P<version>\d+\.\d+)$`,
+			name:     "version-2.1",
+			expected: Version{Name: "version-2.1", Major: 2, Minor: 1},
+			ok:       true,
+		},
+		{
+			desc:     "first group",
+			pattern:  `^(\d+\.\d+)-docs$`,
+			name:     "2.4-docs",
+			expected: Version{Name: "2.4-docs", Major: 2, Minor: 4},
+			ok:       true,
+		},
+		{
+			desc:     "whole name",
+			pattern:  `^\d+\.\d+$`,
+			name:     "2.1",
+			expected: Version{Name: "2.1", Major: 2, Minor: 1},
+			ok:       true,
+		},
+		{
+			desc:    "not a version",
+			pattern: `^version-(?P<version>.+)$`,
+			name:    "version-next",
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			folders, err := NewFolders(test.pattern)
+			require.NoError(t, err)
+
+			v, ok := folders.Parse(test.name)
+			assert.Equal(t, test.ok, ok)
+			assert.Equal(t, test.expected, v)
+		})
+	}
+}
+
+func TestNewFolders_invalid(t *testing.T) {
+	_, err := NewFolders(`^version-(`)
+	require.Error(t, err)
+}
+
+func TestFolders_List(t *testing.T) {
+	dir := t.TempDir()
+
+	for _, name := range []string{"v1.7", "v2.0", "master", "assets"} {
+		err := os.MkdirAll(filepath.Join(dir, name), 0o700)
+		require.NoError(t, err)
+	}
+
+	err := os.WriteFile(filepath.Join(dir, "v3.0"), nil, 0o600)
+	require.NoError(t, err)
+
+	folders, err := NewFolders("")
+	require.NoError(t, err)
+
+	set, err := folders.List(dir)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"master", "v2.0", "v1.7"}, names(set))
+}
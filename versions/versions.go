@@ -0,0 +1,182 @@
+// Package versions discovers and sorts the version folders of the documentation of a product.
+package versions
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Development is the folder of the documentation of the development branch.
+const Development = "master"
+
+var expVersion = regexp.MustCompile(`^v?(\d+)\.(\d+)(?:\.(\d+))?(?:-([0-9A-Za-z.]+))?$`)
+
+// Version is a version folder (v2.4, v2.10.3, v3.0-rc1, 2.1, master).
+type Version struct {
+	Name  string
+	Major int
+	Minor int
+	Patch int
+	// Pre is the pre-release suffix (rc1).
+	Pre string
+	// Dev is true for the development branch (master).
+	Dev bool
+}
+
+// Parse parses the name of a version folder.
+func Parse(name string) (Version, bool) {
+	if name == Development {
+		return Version{Name: name, Dev: true}, true
+	}
+
+	parts := expVersion.FindStringSubmatch(name)
+	if parts == nil {
+		return Version{}, false
+	}
+
+	v := Version{Name: name, Pre: parts[4]}
+	v.Major, _ = strconv.Atoi(parts[1])
+	v.Minor, _ = strconv.Atoi(parts[2])
+
+	if parts[3] != "" {
+		v.Patch, _ = strconv.Atoi(parts[3])
+	}
+
+	return v, true
+}
+
+// Stable returns true if the version is neither a pre-release nor the development branch.
+func (v Version) Stable() bool {
+	return !v.Dev && v.Pre == ""
+}
+
+// Compare returns -1 if v is older than o, 1 if v is newer than o, 0 if they are equal.
+// The development branch is newer than all the versions, a pre-release is older than its release.
+func (v Version) Compare(o Version) int {
+	switch {
+	case v.Dev || o.Dev:
+		return compareBool(v.Dev, o.Dev)
+	case v.Major != o.Major:
+		return compareInt(v.Major, o.Major)
+	case v.Minor != o.Minor:
+		return compareInt(v.Minor, o.Minor)
+	case v.Patch != o.Patch:
+		return compareInt(v.Patch, o.Patch)
+	case v.Pre == o.Pre:
+		return 0
+	case v.Pre == "" || o.Pre == "":
+		return compareBool(v.Pre == "", o.Pre == "")
+	default:
+		return comparePre(v.Pre, o.Pre)
+	}
+}
+
+// Set is a list of versions, from the newest to the oldest.
+type Set []Version
+
+// NewSet creates a Set from the names of the version folders, the other names are ignored.
+func NewSet(names []string) Set {
+	var set Set
+
+	for _, name := range names {
+		if v, ok := Parse(name); ok {
+			set = append(set, v)
+		}
+	}
+
+	set.sort()
+
+	return set
+}
+
+// sort sorts the versions from the newest to the oldest.
+func (s Set) sort() {
+	sort.SliceStable(s, func(i, j int) bool {
+		return s[i].Compare(s[j]) > 0
+	})
+}
+
+// Newest returns the newest stable version.
+// The latest documentation is the unversioned root folder: the newest version folder holds the previous minor version.
+func (s Set) Newest() (Version, bool) {
+	for _, v := range s {
+		if v.Stable() {
+			return v, true
+		}
+	}
+
+	return Version{}, false
+}
+
+// MajorsBehind returns the number of major versions between a version and the newest stable version.
+// It returns 0 for the newest major version, the newer versions, and when there is no stable version.
+func (s Set) MajorsBehind(v Version) int {
+	newest, ok := s.Newest()
+	if !ok || v.Dev || v.Major >= newest.Major {
+		return 0
+	}
+
+	return newest.Major - v.Major
+}
+
+// IsEOL returns true if a version is older than the supported major versions (counted from the newest major version).
+// Zero supported major versions disables the rule.
+func (s Set) IsEOL(v Version, supportedMajors int) bool {
+	return supportedMajors > 0 && s.MajorsBehind(v) >= supportedMajors
+}
+
+// CompareNames compares the names of two version folders, a name that is not a version is older than all the versions.
+func CompareNames(a, b string) int {
+	va, okA := Parse(a)
+	vb, okB := Parse(b)
+
+	switch {
+	case okA && okB:
+		return va.Compare(vb)
+	case okA != okB:
+		return compareBool(okA, okB)
+	default:
+		return strings.Compare(a, b)
+	}
+}
+
+var expPreNumber = regexp.MustCompile(`^(\D*)(\d*)$`)
+
+// comparePre compares the pre-release suffixes (rc2 < rc10).
+func comparePre(a, b string) int {
+	pa := expPreNumber.FindStringSubmatch(a)
+	pb := expPreNumber.FindStringSubmatch(b)
+
+	if pa != nil && pb != nil && pa[1] == pb[1] && pa[2] != "" && pb[2] != "" {
+		x, _ := strconv.Atoi(pa[2])
+		y, _ := strconv.Atoi(pb[2])
+
+		return compareInt(x, y)
+	}
+
+	return strings.Compare(a, b)
+}
+
+func compareInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func compareBool(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case a:
+		return 1
+	default:
+		return -1
+	}
+}
@@ -0,0 +1,135 @@
+package versions
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		name     string
+		expected Version
+		ok       bool
+	}{
+		{name: "v2.4", expected: Version{Name: "v2.4", Major: 2, Minor: 4}, ok: true},
+		{name: "v2.10.3", expected: Version{Name: "v2.10.3", Major: 2, Minor: 10, Patch: 3}, ok: true},
+		{name: "v3.0-rc1", expected: Version{Name: "v3.0-rc1", Major: 3, Pre: "rc1"}, ok: true},
+		{name: "master", expected: Version{Name: "master", Dev: true}, ok: true},
+		{name: "v2"},
+		{name: "v2.4-"},
+		{name: "2.4", expected: Version{Name: "2.4", Major: 2, Minor: 4}, ok: true},
+		{name: "assets"},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			v, ok := Parse(test.name)
+			assert.Equal(t, test.ok, ok)
+			assert.Equal(t, test.expected, v)
+		})
+	}
+}
+
+func TestNewSet(t *testing.T) {
+	set := NewSet([]string{"v1.7", "v2.10", "assets", "v2.9", "v3.0-rc10", "v2.9.1", "master", "v3.0-rc2", "v1.10", "v3.0"})
+
+	expected := []string{"master", "v3.0", "v3.0-rc10", "v3.0-rc2", "v2.10", "v2.9.1", "v2.9", "v1.10", "v1.7"}
+
+	assert.Equal(t, expected, names(set))
+}
+
+func TestSet_Newest(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		names  []string
+		newest string
+	}{
+		{
+			desc:   "stable versions",
+			names:  []string{"v2.9", "v2.10", "v2.10.1", "v1.7"},
+			newest: "v2.10.1",
+		},
+		{
+			desc:   "development and pre-release",
+			names:  []string{"master", "v3.0-rc1", "v2.10", "v1.7"},
+			newest: "v2.10",
+		},
+		{
+			desc:   "single version",
+			names:  []string{"v2.10"},
+			newest: "v2.10",
+		},
+		{
+			desc:  "no stable version",
+			names: []string{"master", "v3.0-rc1"},
+		},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.desc, func(t *testing.T) {
+			t.Parallel()
+
+			set := NewSet(test.names)
+
+			newest, ok := set.Newest()
+			assert.Equal(t, test.newest != "", ok)
+			assert.Equal(t, test.newest, newest.Name)
+		})
+	}
+}
+
+func TestSet_IsEOL(t *testing.T) {
+	set := NewSet([]string{"master", "v3.1", "v3.0", "v2.11", "v1.7"})
+
+	testCases := []struct {
+		version   string
+		supported int
+		expected  bool
+	}{
+		{version: "v3.0", supported: 1, expected: false},
+		{version: "v2.11", supported: 1, expected: true},
+		{version: "v2.11", supported: 2, expected: false},
+		{version: "v1.7", supported: 2, expected: true},
+		{version: "v1.7", supported: 0, expected: false},
+		{version: "master", supported: 1, expected: false},
+	}
+
+	for _, test := range testCases {
+		test := test
+		t.Run(test.version, func(t *testing.T) {
+			t.Parallel()
+
+			v, ok := Parse(test.version)
+			require.True(t, ok)
+
+			assert.Equal(t, test.expected, set.IsEOL(v, test.supported))
+		})
+	}
+}
+
+func TestCompareNames(t *testing.T) {
+	names := []string{"v1.7", "latest", "v2.10", "v2.9", "v2.9.1", "v1.10", "archive"}
+
+	sort.SliceStable(names, func(i, j int) bool {
+		return CompareNames(names[i], names[j]) > 0
+	})
+
+	assert.Equal(t, []string{"v2.10", "v2.9.1", "v2.9", "v1.10", "v1.7", "latest", "archive"}, names)
+}
+
+// names returns the names of the versions of a set.
+func names(set Set) []string {
+	var names []string
+	for _, v := range set {
+		names = append(names, v.Name)
+	}
+
+	return names
+}